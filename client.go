@@ -1,5 +1,7 @@
 package battleships
 
+import "fmt"
+
 type Client interface {
 	InitGame(data GamePost) (Game, error)
 	Abandon(game Game) error
@@ -19,6 +21,20 @@ type Client interface {
 	Fire(game Game, field string) (ShotState, error)
 }
 
+// ServerError is returned when the server answers the request with an error code.
+type ServerError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e ServerError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("Server returned code %d", e.StatusCode)
+	}
+
+	return fmt.Sprintf("Server returned code %d. Message: %s", e.StatusCode, e.Message)
+}
+
 type Status string
 
 const (
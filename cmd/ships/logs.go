@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// deferredWriter holds the log lines back while the TUI owns the terminal, as they would garble the screen,
+// and writes them out once it's restored.
+type deferredWriter struct {
+	mu       sync.Mutex
+	out      io.Writer
+	buffer   bytes.Buffer
+	deferred bool
+}
+
+func (w *deferredWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.deferred {
+		return w.buffer.Write(p)
+	}
+
+	return w.out.Write(p)
+}
+
+// Defer starts holding the lines back.
+func (w *deferredWriter) Defer() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.deferred = true
+}
+
+// Flush writes out the lines held back, and lets the next ones through.
+func (w *deferredWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.deferred = false
+	_, err := w.buffer.WriteTo(w.out)
+
+	return err
+}
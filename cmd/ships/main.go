@@ -2,16 +2,26 @@ package main
 
 import (
 	"context"
+	"flag"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
 	battleships "github.com/kovansky/wp-battleships"
+	"github.com/kovansky/wp-battleships/config"
+	"github.com/kovansky/wp-battleships/routines"
 	"github.com/kovansky/wp-battleships/ships"
-	"github.com/kovansky/wp-battleships/tui"
+	"github.com/kovansky/wp-battleships/tui/themes"
 	"github.com/kovansky/wp-battleships/tui/wrapper"
+	"github.com/muesli/termenv"
 	"github.com/rs/zerolog"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 )
 
+// shutdownTimeout is how long abandoning the game and stopping the routines may take, after the program has exited
+const shutdownTimeout = 3 * time.Second
+
 var (
 	Version = "v0.0.1"
 	log     zerolog.Logger
@@ -22,66 +32,96 @@ func main() {
 	battleships.Version = Version
 
 	// Create logger
+	logs := &deferredWriter{out: os.Stdout}
 	log = zerolog.
-		New(os.Stdout).
+		New(logs).
 		With().Timestamp().
 		Logger().
-		Output(zerolog.ConsoleWriter{Out: os.Stdout})
+		Output(zerolog.ConsoleWriter{Out: logs})
+
+	// Load settings
+	settingsPath := flag.String("config", config.DefaultPath(), "path to the settings file")
+	themeName := flag.String("theme", "", "built-in theme ("+strings.Join(themes.Presets, ", ")+") or path to a theme file")
+	flag.Parse()
+
+	settings, err := config.Load(*settingsPath)
+	if err != nil {
+		log.Warn().Err(err).Str("path", *settingsPath).Msg("Could not load settings, using defaults")
+	}
+	if *themeName != "" {
+		settings.Theme = *themeName
+	}
 
-	// Setup signal handlers
+	keys, err := battleships.DefaultKeyMap().Apply(settings.Keys)
+	if err != nil {
+		log.Warn().Err(err).Msg("Some key bindings could not be set")
+	}
+	for _, conflict := range keys.Conflicts() {
+		log.Warn().Msg("Conflicting key binding: " + conflict.String())
+	}
+
+	// Create context
 	ctx, cancel := context.WithCancel(context.Background())
 	ctx = context.WithValue(ctx, battleships.ContextKeyLog, log)
 	defer cancel()
 
 	// Create client
-	battleships.ServerClient = ships.NewClient(ctx, "https://go-pjatk-server.fly.dev/api", &log)
-
-	// Initialize ships
-	colRowStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#00ff7f")).
-		Bold(true)
-	theme := tui.NewTheme().
-		SetRows(colRowStyle).
-		SetCols(colRowStyle).
-		SetShip(tui.NewBrush().
-			SetChar('X').
-			SetStyle(lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#1e90ff")))).
-		SetHit(tui.NewBrush().
-			SetChar('X').
-			SetStyle(lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#00ff7f")))).
-		SetSunk(tui.NewBrush().
-			SetChar('-').
-			SetStyle(lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#006332")))).
-		SetMiss(tui.NewBrush().
-			SetChar('o').
-			SetStyle(lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#ff0000")))).
-		SetPotential(tui.NewBrush().
-			SetChar('o').
-			SetStyle(lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#e06c00"))))
-	globalTheme := tui.NewTheme().
-		SetTextPrimary(lipgloss.NewStyle().Foreground(lipgloss.Color("#ffd700"))).
-		SetTextSecondary(lipgloss.NewStyle().Foreground(lipgloss.Color("#1e90ff")))
-
-	battleships.Themes = battleships.GameThemes{
-		Player: theme,
-		Enemy:  theme,
-		Global: globalTheme,
+	client := ships.NewClient(ctx, "https://go-pjatk-server.fly.dev/api", &log)
+
+	// Load themes. Without enough colours, the fields can be told apart only by the glyphs
+	if themes.LowColor(termenv.NewOutput(os.Stdout)) && settings.Theme != themes.Fallback {
+		log.Info().Str("theme", themes.Fallback).Msg("The terminal shows too few colours, using a theme without them")
+		settings.Theme = themes.Fallback
 	}
 
-	applicationWrapper := wrapper.Create(ctx, globalTheme)
+	gameThemes, err := themes.Resolve(settings.Theme)
+	if err != nil {
+		log.Warn().Err(err).Str("theme", settings.Theme).Msg("Could not load theme, using the default one")
+
+		settings.Theme = themes.Default
+		gameThemes, _ = themes.Preset(themes.Default)
+	}
 
-	program := tea.NewProgram(applicationWrapper, tea.WithAltScreen())
+	session := battleships.NewSession(client, gameThemes, settings)
+	session.Keys = keys
 
-	battleships.ProgramMessage = func(msg tea.Msg) {
-		program.Send(msg)
+	applicationWrapper := wrapper.Create(ctx, session, gameThemes.Global)
+
+	program := tea.NewProgram(applicationWrapper, tea.WithAltScreen(), tea.WithMouseCellMotion())
+
+	session.Send = program.Send
+	session.Routines = routines.NewSupervisor(ctx, session.Send)
+
+	// The player's own theme is applied again whenever the file is saved
+	if !themes.IsPreset(settings.Theme) {
+		session.Routines.Start(routines.CreateTheme(ctx, session, settings.Theme))
 	}
 
+	// Quit the program on signals too, so the terminal is restored and the game is abandoned
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	go func() {
+		select {
+		case sig := <-signals:
+			log.Info().Str("signal", sig.String()).Msg("Shutting down")
+			program.Quit()
+		case <-ctx.Done():
+		}
+	}()
+
+	logs.Defer()
+
 	if _, err := program.Run(); err != nil {
 		log.Error().Err(err).Msg("Could not draw board")
 	}
+
+	signal.Stop(signals)
+
+	if !session.Close(shutdownTimeout) {
+		log.Warn().Msg("Could not abandon the game and stop the background routines in time")
+	}
+
+	if err := logs.Flush(); err != nil {
+		_, _ = os.Stderr.WriteString("Could not write the logs: " + err.Error() + "\n")
+	}
 }
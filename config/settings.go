@@ -0,0 +1,42 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	battleships "github.com/kovansky/wp-battleships"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const appDirectory = "wp-battleships"
+
+// DefaultPath returns the location of the settings file in the user's config directory.
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "settings.json"
+	}
+
+	return filepath.Join(dir, appDirectory, "settings.json")
+}
+
+// Load reads the settings file. Missing file or missing keys fall back to the defaults.
+func Load(path string) (battleships.Settings, error) {
+	settings := battleships.DefaultSettings()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return settings, nil
+		}
+
+		return settings, err
+	}
+
+	if err = json.Unmarshal(content, &settings); err != nil {
+		return battleships.DefaultSettings(), err
+	}
+
+	return settings, nil
+}
@@ -0,0 +1,38 @@
+package config_test
+
+import (
+	battleships "github.com/kovansky/wp-battleships"
+	"github.com/kovansky/wp-battleships/config"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	settings, err := config.Load(filepath.Join(dir, "missing.json"))
+	if err != nil {
+		t.Fatalf("Received unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(settings, battleships.DefaultSettings()) {
+		t.Fatalf("Expected default settings, got: %+v", settings)
+	}
+
+	path := filepath.Join(dir, "settings.json")
+	if err = os.WriteFile(path, []byte(`{"timer": {"auto_fire": true}}`), 0o600); err != nil {
+		t.Fatalf("Received unexpected error: %v", err)
+	}
+
+	settings, err = config.Load(path)
+	if err != nil {
+		t.Fatalf("Received unexpected error: %v", err)
+	}
+	if !settings.Timer.AutoFire {
+		t.Fatalf("Setting from file has not been loaded")
+	}
+	if settings.Timer.WarningThreshold != battleships.DefaultSettings().Timer.WarningThreshold {
+		t.Fatalf("Missing setting did not fall back to default, got: %d", settings.Timer.WarningThreshold)
+	}
+}
@@ -0,0 +1,47 @@
+package battleships
+
+// GameEvent is published by the Game whenever its state changes. Events are also tea messages,
+// so they can be forwarded to the TUI as they are.
+type GameEvent interface {
+	gameEvent()
+}
+
+// ShotFired is our shot at the opponent's board.
+type ShotFired struct {
+	Field  string
+	Result FieldState
+}
+
+// ShotReceived is the opponent's shot at our board.
+type ShotReceived struct {
+	Field  string
+	Result FieldState
+}
+
+// TurnChanged is published when the turn passes from one player to the other, or the game starts.
+type TurnChanged struct {
+	ShouldFire bool
+	Timer      int
+}
+
+// StatusChanged is published when the polled status changes within the same turn, e.g. the timer.
+type StatusChanged struct {
+	Timer int
+}
+
+// OpponentJoined is published once the opponent is known.
+type OpponentJoined struct {
+	Opponent Player
+}
+
+// GameEnded carries the final status, either StatusWin or StatusLose.
+type GameEnded struct {
+	Result Status
+}
+
+func (ShotFired) gameEvent()      {}
+func (ShotReceived) gameEvent()   {}
+func (TurnChanged) gameEvent()    {}
+func (StatusChanged) gameEvent()  {}
+func (OpponentJoined) gameEvent() {}
+func (GameEnded) gameEvent()      {}
@@ -1,5 +1,9 @@
 package battleships
 
+import "time"
+
+// Game is the state of a single game. It is safe for concurrent use: getters return copies,
+// and every change is published to the subscribers as a GameEvent.
 type Game interface {
 	Key() string
 
@@ -13,10 +17,29 @@ type Game interface {
 	SetOpponentBoard(board map[string]FieldState)
 	OpponentBoard() map[string]FieldState
 
+	// RecordShot marks the result of our shot on the opponent board and counts it in the statistics.
+	RecordShot(field string, result FieldState)
+	// RecordOpponentShots applies the opponent's shots to our board.
+	RecordOpponentShots(fields []string)
+	// History returns the shots of both players, in the order they were recorded.
+	History() []Shot
+	// SetFleet tells which fields of our board make up each ship, so the ships the opponent has fully hit
+	// are marked as sunk.
+	SetFleet(fleet [][]string)
+	// Fleet returns the damage of our ships, in the order they were set.
+	Fleet() []ShipDamage
+
+	// SetAnnotations stores the player's own notes about the opponent board, separately from the real board.
+	SetAnnotations(annotations map[string]Annotation)
+	Annotations() map[string]Annotation
+
 	SetGameStatus(status GameStatus)
 	GameStatus() GameStatus
 
 	Statistics() *Statistics
+
+	// Subscribe returns the channel receiving the game events, and the function ending the subscription.
+	Subscribe() (<-chan GameEvent, func())
 }
 
 type GameStatus struct {
@@ -26,8 +49,6 @@ type GameStatus struct {
 	Timer      int
 }
 
-type GameUpdateMsg struct{}
-
 type PlayersUpdateMsg struct {
 	PlayersInfo string
 }
@@ -45,6 +66,38 @@ const (
 	FieldStateSunk            = "sunk"
 )
 
+// Annotation is a player's mark on an unshot opponent field.
+// Besides the predefined marks it may be any single letter from A to Z.
+type Annotation string
+
+const (
+	AnnotationSuspected Annotation = "suspected"
+	AnnotationRuledOut  Annotation = "ruled_out"
+)
+
+func (a Annotation) IsLetter() bool {
+	return len(a) == 1
+}
+
+// Shot is an entry in the history of the game.
+type Shot struct {
+	// Opponent is set for the opponent's shots at our board
+	Opponent bool
+	Field    string
+	Result   FieldState
+	Time     time.Time
+}
+
+// ShipDamage tells how many fields of one of our ships the opponent has hit.
+type ShipDamage struct {
+	Fields []string
+	Hits   int
+}
+
+func (s ShipDamage) Sunk() bool {
+	return len(s.Fields) > 0 && s.Hits == len(s.Fields)
+}
+
 type Field struct {
 	Coord string
 	State FieldState
@@ -4,10 +4,16 @@ go 1.19
 
 require (
 	github.com/76creates/stickers v1.3.0
+	github.com/BurntSushi/toml v1.2.1
 	github.com/charmbracelet/bubbles v0.15.0
 	github.com/charmbracelet/bubbletea v0.23.2
 	github.com/charmbracelet/lipgloss v0.7.1
+	github.com/fsnotify/fsnotify v1.6.0
+	github.com/mattn/go-runewidth v0.0.14
 	github.com/mbndr/figlet4go v0.0.0-20190224160619-d6cef5b186ea
+	github.com/muesli/reflow v0.3.0
+	github.com/muesli/termenv v0.15.1
+	github.com/rivo/uniseg v0.4.3
 	github.com/rs/zerolog v1.29.1
 )
 
@@ -19,12 +25,8 @@ require (
 	github.com/mattn/go-colorable v0.1.12 // indirect
 	github.com/mattn/go-isatty v0.0.17 // indirect
 	github.com/mattn/go-localereader v0.0.1 // indirect
-	github.com/mattn/go-runewidth v0.0.14 // indirect
 	github.com/muesli/ansi v0.0.0-20221106050444-61f0cd9a192a // indirect
 	github.com/muesli/cancelreader v0.2.2 // indirect
-	github.com/muesli/reflow v0.3.0 // indirect
-	github.com/muesli/termenv v0.15.1 // indirect
-	github.com/rivo/uniseg v0.4.3 // indirect
 	golang.org/x/sync v0.1.0 // indirect
 	golang.org/x/sys v0.6.0 // indirect
 	golang.org/x/term v0.4.0 // indirect
@@ -0,0 +1,392 @@
+package battleships
+
+import (
+	"fmt"
+	"github.com/charmbracelet/bubbles/key"
+	"sort"
+	"strings"
+)
+
+// KeyMap holds the key bindings of all stages.
+type KeyMap struct {
+	Global  GlobalKeys
+	Board   BoardKeys
+	Login   LoginKeys
+	Setup   SetupKeys
+	Lobby   LobbyKeys
+	Ranking RankingKeys
+	Game    GameKeys
+	End     EndKeys
+	Error   ErrorKeys
+	Confirm ConfirmKeys
+}
+
+// GlobalKeys work in every stage.
+type GlobalKeys struct {
+	Help  key.Binding
+	Back  key.Binding
+	Theme key.Binding
+	// Notifications shows the notifications shown so far
+	Notifications key.Binding
+	Debug         key.Binding
+	Quit          key.Binding
+}
+
+// BoardKeys move the cursor over a board.
+type BoardKeys struct {
+	Up    key.Binding
+	Down  key.Binding
+	Left  key.Binding
+	Right key.Binding
+}
+
+type LoginKeys struct {
+	Next     key.Binding
+	Previous key.Binding
+	Submit   key.Binding
+}
+
+type SetupKeys struct {
+	Focus     key.Binding
+	Undo      key.Binding
+	Redo      key.Binding
+	ShipClass key.Binding
+	Rotate    key.Binding
+	Place     key.Binding
+	PickUp    key.Binding
+	Fill      key.Binding
+	Submit    key.Binding
+}
+
+type LobbyKeys struct {
+	Up          key.Binding
+	Down        key.Binding
+	Challenge   key.Binding
+	ClearFilter key.Binding
+	Ranking     key.Binding
+}
+
+type RankingKeys struct {
+	Up    key.Binding
+	Down  key.Binding
+	Lobby key.Binding
+}
+
+type GameKeys struct {
+	Focus      key.Binding
+	Fire       key.Binding
+	Queue      key.Binding
+	ClearQueue key.Binding
+	Suspected  key.Binding
+	RuledOut   key.Binding
+	Mark       key.Binding
+	Unmark     key.Binding
+	Leave      key.Binding
+	// SwitchBoard shows the other board, when only one of them fits in the terminal
+	SwitchBoard key.Binding
+	// HistoryUp and HistoryDown scroll the list of shots
+	HistoryUp   key.Binding
+	HistoryDown key.Binding
+}
+
+// EndKeys choose what comes after the game has ended.
+type EndKeys struct {
+	Rematch   key.Binding
+	PlayAgain key.Binding
+	Lobby     key.Binding
+	Setup     key.Binding
+}
+
+// ErrorKeys choose what to do after a persistent error.
+type ErrorKeys struct {
+	Retry   key.Binding
+	Lobby   key.Binding
+	Quit    key.Binding
+	Dismiss key.Binding
+}
+
+type ConfirmKeys struct {
+	Yes key.Binding
+	No  key.Binding
+}
+
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Global: GlobalKeys{
+			Help:          key.NewBinding(key.WithKeys("f1"), key.WithHelp("f1", "toggle help")),
+			Back:          key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "go back")),
+			Theme:         key.NewBinding(key.WithKeys("ctrl+t"), key.WithHelp("ctrl+t", "switch theme")),
+			Notifications: key.NewBinding(key.WithKeys("ctrl+n"), key.WithHelp("ctrl+n", "toggle notifications")),
+			Debug:         key.NewBinding(key.WithKeys("ctrl+d"), key.WithHelp("ctrl+d", "toggle routines view")),
+			Quit:          key.NewBinding(key.WithKeys("ctrl+c"), key.WithHelp("ctrl+c", "quit")),
+		},
+		Board: BoardKeys{
+			Up:    key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "move up")),
+			Down:  key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "move down")),
+			Left:  key.NewBinding(key.WithKeys("left", "h"), key.WithHelp("←/h", "move left")),
+			Right: key.NewBinding(key.WithKeys("right", "l"), key.WithHelp("→/l", "move right")),
+		},
+		Login: LoginKeys{
+			Next:     key.NewBinding(key.WithKeys("tab", "down"), key.WithHelp("tab/↓", "next field")),
+			Previous: key.NewBinding(key.WithKeys("shift+tab", "up"), key.WithHelp("shift+tab/↑", "previous field")),
+			Submit:   key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "next field, submit")),
+		},
+		Setup: SetupKeys{
+			Focus:     key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "switch board/typing")),
+			Undo:      key.NewBinding(key.WithKeys("ctrl+z"), key.WithHelp("ctrl+z", "undo")),
+			Redo:      key.NewBinding(key.WithKeys("ctrl+y"), key.WithHelp("ctrl+y", "redo")),
+			ShipClass: key.NewBinding(key.WithKeys("1", "2", "3", "4"), key.WithHelp("1-4", "ship size")),
+			Rotate:    key.NewBinding(key.WithKeys("r", "R"), key.WithHelp("r", "rotate")),
+			Place:     key.NewBinding(key.WithKeys("enter", " "), key.WithHelp("enter/space", "place ship")),
+			PickUp:    key.NewBinding(key.WithKeys("delete", "backspace", "x"), key.WithHelp("x/del", "pick up ship")),
+			Fill:      key.NewBinding(key.WithKeys("f"), key.WithHelp("f", "fill the rest")),
+			Submit:    key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "run typed command")),
+		},
+		Lobby: LobbyKeys{
+			Up:          key.NewBinding(key.WithKeys("up"), key.WithHelp("↑", "move up")),
+			Down:        key.NewBinding(key.WithKeys("down"), key.WithHelp("↓", "move down")),
+			Challenge:   key.NewBinding(key.WithKeys("enter", " "), key.WithHelp("enter", "challenge player")),
+			ClearFilter: key.NewBinding(key.WithKeys("backspace"), key.WithHelp("backspace", "erase filter")),
+			Ranking:     key.NewBinding(key.WithKeys("R"), key.WithHelp("R", "ranking")),
+		},
+		Ranking: RankingKeys{
+			Up:    key.NewBinding(key.WithKeys("up"), key.WithHelp("↑", "move up")),
+			Down:  key.NewBinding(key.WithKeys("down"), key.WithHelp("↓", "move down")),
+			Lobby: key.NewBinding(key.WithKeys("L", "l"), key.WithHelp("l", "lobby")),
+		},
+		Game: GameKeys{
+			Focus:       key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "switch board/typing")),
+			Fire:        key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "fire, or queue")),
+			Queue:       key.NewBinding(key.WithKeys("q"), key.WithHelp("q", "toggle queued shot")),
+			ClearQueue:  key.NewBinding(key.WithKeys("Q"), key.WithHelp("Q", "clear queue")),
+			Suspected:   key.NewBinding(key.WithKeys("m"), key.WithHelp("m", "mark suspected")),
+			RuledOut:    key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "mark ruled out")),
+			Mark:        key.NewBinding(key.WithKeys("'"), key.WithHelp("'", "custom mark, followed by a letter")),
+			Unmark:      key.NewBinding(key.WithKeys("backspace", "delete"), key.WithHelp("backspace", "remove mark")),
+			Leave:       key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "leave game")),
+			SwitchBoard: key.NewBinding(key.WithKeys("v"), key.WithHelp("v", "show the other board")),
+			HistoryUp:   key.NewBinding(key.WithKeys("pgup"), key.WithHelp("pgup", "earlier shots")),
+			HistoryDown: key.NewBinding(key.WithKeys("pgdown"), key.WithHelp("pgdown", "later shots")),
+		},
+		End: EndKeys{
+			Rematch:   key.NewBinding(key.WithKeys("r", "R"), key.WithHelp("r", "rematch")),
+			PlayAgain: key.NewBinding(key.WithKeys("p", "P"), key.WithHelp("p", "play again")),
+			Lobby:     key.NewBinding(key.WithKeys("l", "L"), key.WithHelp("l", "back to lobby")),
+			Setup:     key.NewBinding(key.WithKeys("s", "S"), key.WithHelp("s", "new setup")),
+		},
+		Error: ErrorKeys{
+			Retry:   key.NewBinding(key.WithKeys("r", "R"), key.WithHelp("r", "retry")),
+			Lobby:   key.NewBinding(key.WithKeys("b", "B"), key.WithHelp("b", "back to lobby")),
+			Quit:    key.NewBinding(key.WithKeys("q", "Q"), key.WithHelp("q", "quit")),
+			Dismiss: key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "dismiss")),
+		},
+		Confirm: ConfirmKeys{
+			Yes: key.NewBinding(key.WithKeys("y", "Y"), key.WithHelp("y", "yes")),
+			No:  key.NewBinding(key.WithKeys("n", "N", "esc"), key.WithHelp("n/esc", "no")),
+		},
+	}
+}
+
+// KeySettings override the default key bindings, listing the keys of the actions by section,
+// e.g. {"board": {"up": ["up", "w"]}}. An empty list unbinds the action.
+type KeySettings map[string]map[string][]string
+
+type keyAction struct {
+	section, name string
+	binding       *key.Binding
+}
+
+func (a keyAction) String() string {
+	return a.section + "." + a.name
+}
+
+// actions lists the bindings of the key map, by the names used in the settings file.
+func (m *KeyMap) actions() []keyAction {
+	return []keyAction{
+		{"global", "help", &m.Global.Help},
+		{"global", "back", &m.Global.Back},
+		{"global", "theme", &m.Global.Theme},
+		{"global", "notifications", &m.Global.Notifications},
+		{"global", "debug", &m.Global.Debug},
+		{"global", "quit", &m.Global.Quit},
+		{"board", "up", &m.Board.Up},
+		{"board", "down", &m.Board.Down},
+		{"board", "left", &m.Board.Left},
+		{"board", "right", &m.Board.Right},
+		{"login", "next", &m.Login.Next},
+		{"login", "previous", &m.Login.Previous},
+		{"login", "submit", &m.Login.Submit},
+		{"setup", "focus", &m.Setup.Focus},
+		{"setup", "undo", &m.Setup.Undo},
+		{"setup", "redo", &m.Setup.Redo},
+		{"setup", "ship_class", &m.Setup.ShipClass},
+		{"setup", "rotate", &m.Setup.Rotate},
+		{"setup", "place", &m.Setup.Place},
+		{"setup", "pick_up", &m.Setup.PickUp},
+		{"setup", "fill", &m.Setup.Fill},
+		{"setup", "submit", &m.Setup.Submit},
+		{"lobby", "up", &m.Lobby.Up},
+		{"lobby", "down", &m.Lobby.Down},
+		{"lobby", "challenge", &m.Lobby.Challenge},
+		{"lobby", "clear_filter", &m.Lobby.ClearFilter},
+		{"lobby", "ranking", &m.Lobby.Ranking},
+		{"ranking", "up", &m.Ranking.Up},
+		{"ranking", "down", &m.Ranking.Down},
+		{"ranking", "lobby", &m.Ranking.Lobby},
+		{"game", "focus", &m.Game.Focus},
+		{"game", "fire", &m.Game.Fire},
+		{"game", "queue", &m.Game.Queue},
+		{"game", "clear_queue", &m.Game.ClearQueue},
+		{"game", "suspected", &m.Game.Suspected},
+		{"game", "ruled_out", &m.Game.RuledOut},
+		{"game", "mark", &m.Game.Mark},
+		{"game", "unmark", &m.Game.Unmark},
+		{"game", "leave", &m.Game.Leave},
+		{"game", "switch_board", &m.Game.SwitchBoard},
+		{"game", "history_up", &m.Game.HistoryUp},
+		{"game", "history_down", &m.Game.HistoryDown},
+		{"end", "rematch", &m.End.Rematch},
+		{"end", "play_again", &m.End.PlayAgain},
+		{"end", "lobby", &m.End.Lobby},
+		{"end", "setup", &m.End.Setup},
+		{"error", "retry", &m.Error.Retry},
+		{"error", "lobby", &m.Error.Lobby},
+		{"error", "quit", &m.Error.Quit},
+		{"error", "dismiss", &m.Error.Dismiss},
+		{"confirm", "yes", &m.Confirm.Yes},
+		{"confirm", "no", &m.Confirm.No},
+	}
+}
+
+// Apply overrides the bindings with the ones from the settings. Unknown actions are reported, the rest is applied.
+func (m KeyMap) Apply(settings KeySettings) (KeyMap, error) {
+	var unknown []string
+
+	for section, actions := range settings {
+		for name, keys := range actions {
+			action, ok := m.action(section, name)
+			if !ok {
+				unknown = append(unknown, section+"."+name)
+				continue
+			}
+
+			action.binding.SetKeys(keys...)
+			action.binding.SetHelp(keyHelp(keys), action.binding.Help().Desc)
+			action.binding.SetEnabled(len(keys) > 0)
+		}
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return m, fmt.Errorf("unknown key actions: %s", strings.Join(unknown, ", "))
+	}
+
+	return m, nil
+}
+
+// action looks the binding up in the key map. The binding points to the key map the method is called on.
+func (m *KeyMap) action(section, name string) (keyAction, bool) {
+	for _, action := range m.actions() {
+		if action.section == section && action.name == name {
+			return action, true
+		}
+	}
+
+	return keyAction{}, false
+}
+
+// keyNames are shown in the help instead of the names of the keys.
+var keyNames = map[string]string{
+	"up":    "↑",
+	"down":  "↓",
+	"left":  "←",
+	"right": "→",
+	" ":     "space",
+}
+
+func keyHelp(keys []string) string {
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		names[i] = k
+		if name, ok := keyNames[k]; ok {
+			names[i] = name
+		}
+	}
+
+	return strings.Join(names, "/")
+}
+
+// keyScopes lists the actions which are active at the same time, so they must not share keys.
+var keyScopes = []struct {
+	name    string
+	actions []string
+}{
+	{"login", []string{"global.help", "global.theme", "global.notifications", "global.debug", "global.quit", "login.next", "login.previous", "login.submit"}},
+	{"setup board", []string{"global.help", "global.back", "global.theme", "global.notifications", "global.debug", "global.quit", "setup.focus", "setup.undo", "setup.redo",
+		"board.up", "board.down", "board.left", "board.right",
+		"setup.ship_class", "setup.rotate", "setup.place", "setup.pick_up", "setup.fill"}},
+	{"setup typing", []string{"global.help", "global.back", "global.theme", "global.notifications", "global.debug", "global.quit", "setup.focus", "setup.undo", "setup.redo", "setup.submit"}},
+	{"lobby", []string{"global.help", "global.back", "global.theme", "global.notifications", "global.debug", "global.quit",
+		"lobby.up", "lobby.down", "lobby.challenge", "lobby.clear_filter", "lobby.ranking"}},
+	{"ranking", []string{"global.help", "global.back", "global.theme", "global.notifications", "global.debug", "global.quit", "ranking.up", "ranking.down", "ranking.lobby"}},
+	{"game board", []string{"global.help", "global.theme", "global.notifications", "global.debug", "global.quit",
+		"board.up", "board.down", "board.left", "board.right",
+		"game.focus", "game.fire", "game.queue", "game.clear_queue", "game.leave",
+		"game.suspected", "game.ruled_out", "game.mark", "game.unmark", "game.switch_board",
+		"game.history_up", "game.history_down"}},
+	{"game typing", []string{"global.help", "global.theme", "global.notifications", "global.debug", "global.quit", "game.focus", "game.fire", "game.clear_queue", "game.leave",
+		"game.history_up", "game.history_down"}},
+	{"game end", []string{"global.help", "global.theme", "global.notifications", "global.debug", "global.quit", "end.rematch", "end.play_again", "end.lobby", "end.setup"}},
+	{"leaving the game", []string{"global.help", "global.theme", "global.notifications", "global.debug", "global.quit", "confirm.yes", "confirm.no"}},
+	{"error", []string{"global.theme", "global.notifications", "global.debug", "global.quit", "error.retry", "error.lobby", "error.quit", "error.dismiss"}},
+}
+
+// KeyConflict is a key bound to more than one action, which are active at the same time.
+type KeyConflict struct {
+	Scope   string
+	Key     string
+	Actions []string
+}
+
+func (c KeyConflict) String() string {
+	return fmt.Sprintf("%q is bound to %s (%s)", c.Key, strings.Join(c.Actions, " and "), c.Scope)
+}
+
+// Conflicts lists the keys bound to more than one action within a scope.
+func (m KeyMap) Conflicts() []KeyConflict {
+	var conflicts []KeyConflict
+
+	for _, scope := range keyScopes {
+		var (
+			keys    []string
+			actions = make(map[string][]string)
+		)
+
+		for _, name := range scope.actions {
+			section, action, _ := strings.Cut(name, ".")
+
+			a, ok := m.action(section, action)
+			if !ok || !a.binding.Enabled() {
+				continue
+			}
+
+			for _, k := range a.binding.Keys() {
+				bound, seen := actions[k]
+				if !seen {
+					keys = append(keys, k)
+				}
+				if len(bound) == 0 || bound[len(bound)-1] != name {
+					actions[k] = append(bound, name)
+				}
+			}
+		}
+
+		for _, k := range keys {
+			if len(actions[k]) > 1 {
+				conflicts = append(conflicts, KeyConflict{Scope: scope.name, Key: k, Actions: actions[k]})
+			}
+		}
+	}
+
+	return conflicts
+}
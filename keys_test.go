@@ -0,0 +1,51 @@
+package battleships_test
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	battleships "github.com/kovansky/wp-battleships"
+	"testing"
+)
+
+func TestKeyMap_Conflicts(t *testing.T) {
+	if conflicts := battleships.DefaultKeyMap().Conflicts(); len(conflicts) > 0 {
+		t.Fatalf("Default key bindings should not conflict, got: %v", conflicts)
+	}
+
+	keys, err := battleships.DefaultKeyMap().Apply(battleships.KeySettings{
+		"game": {"suspected": {"x"}},
+	})
+	if err != nil {
+		t.Fatalf("Received unexpected error: %v", err)
+	}
+
+	conflicts := keys.Conflicts()
+	if len(conflicts) != 1 || conflicts[0].Key != "x" || conflicts[0].Scope != "game board" {
+		t.Fatalf("Expected a conflict of suspected and ruled out marks, got: %v", conflicts)
+	}
+}
+
+func TestKeyMap_Apply(t *testing.T) {
+	keys, err := battleships.DefaultKeyMap().Apply(battleships.KeySettings{
+		"board": {"up": {"up", "w"}},
+		"game":  {"leave": {}},
+		"lobby": {"dance": {"d"}},
+	})
+	if err == nil {
+		t.Fatalf("Expected an error about the unknown action")
+	}
+
+	w := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}}
+	if !key.Matches(w, keys.Board.Up) {
+		t.Fatalf("Key from the settings has not been bound")
+	}
+	if keys.Board.Up.Help().Key != "↑/w" {
+		t.Fatalf("Incorrect help; expected: %s, got: %s", "↑/w", keys.Board.Up.Help().Key)
+	}
+	if keys.Game.Leave.Enabled() {
+		t.Fatalf("Action without keys should be unbound")
+	}
+	if key.Matches(w, battleships.DefaultKeyMap().Board.Up) {
+		t.Fatalf("Default key map should not change")
+	}
+}
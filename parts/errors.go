@@ -59,3 +59,23 @@ func NewErrShipSize(size int) ErrShipSize {
 func (e ErrShipSize) Error() string {
 	return fmt.Sprintf("ship size %d is incorrect (allowed sizes: 1, 2, 3, 4)", e.size)
 }
+
+type ErrFleetIncomplete struct {
+	size   int
+	free   int
+	needed int
+}
+
+func NewErrFleetIncomplete(size, free, needed int) ErrFleetIncomplete {
+	return ErrFleetIncomplete{size: size, free: free, needed: needed}
+}
+
+func (e ErrFleetIncomplete) Error() string {
+	if e.size == 0 {
+		return fmt.Sprintf("could not find a legal placement for the remaining ships (%d free fields for %d remaining ship fields, "+
+			"and every ship needs a free border around it)", e.free, e.needed)
+	}
+
+	return fmt.Sprintf("there is no room left for a ship of size %d (%d free fields for %d remaining ship fields, "+
+		"and every ship needs a free border around it)", e.size, e.free, e.needed)
+}
@@ -0,0 +1,197 @@
+package parts
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// fillBudget limits the number of placements tried by FillFleet, so an impossible board does not hang the UI.
+const fillBudget = 20000
+
+// FillFleet randomly places ships of given sizes on the board, avoiding the blocked fields and keeping
+// the ships from touching each other. Returns ErrFleetIncomplete if no legal completion has been found.
+func FillFleet(blocked map[string]State, sizes []int, rng *rand.Rand) ([]Ship, error) {
+	sorted := make([]int, len(sizes))
+	copy(sorted, sizes)
+	// Biggest ships are the hardest to fit, so they go first
+	sort.Sort(sort.Reverse(sort.IntSlice(sorted)))
+
+	f := filler{
+		taken:       make(map[string]bool, 100),
+		rng:         rng,
+		budget:      fillBudget,
+		failedDepth: -1,
+	}
+	for identifier := range blocked {
+		f.taken[identifier] = true
+	}
+
+	ships, ok := f.fill(sorted, 0)
+	if ok {
+		return ships, nil
+	}
+
+	needed := 0
+	for _, size := range sorted {
+		needed += size
+	}
+
+	// Size 0 means we ran out of budget before proving that some ship does not fit
+	failedSize := 0
+	if f.budget > 0 && f.failedDepth >= 0 {
+		failedSize = sorted[f.failedDepth]
+	}
+
+	return nil, NewErrFleetIncomplete(failedSize, 100-len(blocked), needed)
+}
+
+type filler struct {
+	taken  map[string]bool
+	rng    *rand.Rand
+	budget int
+
+	// failedDepth is the deepest position in the sizes list, for which there was no room at all
+	failedDepth int
+}
+
+func (f *filler) fill(sizes []int, depth int) ([]Ship, bool) {
+	if depth == len(sizes) {
+		return nil, true
+	}
+
+	candidates := f.candidates(sizes[depth])
+	if len(candidates) == 0 {
+		if depth > f.failedDepth {
+			f.failedDepth = depth
+		}
+
+		return nil, false
+	}
+
+	f.rng.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+
+	for _, ship := range candidates {
+		if f.budget == 0 {
+			return nil, false
+		}
+		f.budget--
+
+		added := f.take(ship)
+
+		rest, ok := f.fill(sizes, depth+1)
+		if ok {
+			return append(rest, ship), true
+		}
+
+		for _, identifier := range added {
+			delete(f.taken, identifier)
+		}
+	}
+
+	return nil, false
+}
+
+// candidates lists all ships of given size that fit on the free fields.
+func (f *filler) candidates(size int) []Ship {
+	var candidates []Ship
+
+	orientations := []Orientation{Horizontal, Vertical}
+	if size == 1 {
+		orientations = orientations[:1]
+	}
+
+	for numeric := uint8(0); numeric < 100; numeric++ {
+		anchor, _ := NumericToIdentifier(numeric)
+
+		for _, orientation := range orientations {
+			ship, err := NewShipAt(anchor, size, orientation)
+			if err != nil {
+				continue
+			}
+
+			free := true
+			for identifier := range ship.Ship() {
+				if f.taken[identifier] {
+					free = false
+					break
+				}
+			}
+
+			if free {
+				candidates = append(candidates, ship)
+			}
+		}
+	}
+
+	return candidates
+}
+
+// take marks the ship and its surroundings as taken, returning the newly taken fields.
+func (f *filler) take(ship Ship) []string {
+	var added []string
+
+	protected, _ := ship.Protected()
+	for identifier := range protected {
+		if !f.taken[identifier] {
+			f.taken[identifier] = true
+			added = append(added, identifier)
+		}
+	}
+	for identifier := range ship.Ship() {
+		if !f.taken[identifier] {
+			f.taken[identifier] = true
+			added = append(added, identifier)
+		}
+	}
+
+	return added
+}
+
+// SplitFleet groups the fields of a fleet into ships: fields touching by an edge belong to the same ship.
+// The ships are ordered by their fields, see Ship.Fields.
+func SplitFleet(fields []string) ([]Ship, error) {
+	remaining := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		remaining[field] = true
+	}
+
+	var ships []Ship
+	for numeric := uint8(0); numeric < 100; numeric++ {
+		identifier, _ := NumericToIdentifier(numeric)
+		if !remaining[identifier] {
+			continue
+		}
+		delete(remaining, identifier)
+
+		ship := NewShip()
+		queue := []string{identifier}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+
+			var err error
+			if ship, err = ship.Add(current); err != nil {
+				return nil, err
+			}
+
+			f, _ := NewField(current)
+			for _, direction := range []string{"N", "S", "W", "E"} {
+				if next, ok := f.Adjacent()[direction]; ok && remaining[next] {
+					delete(remaining, next)
+					queue = append(queue, next)
+				}
+			}
+		}
+
+		ships = append(ships, ship)
+	}
+
+	// Whatever is left is not a field of the board
+	for field := range remaining {
+		return nil, NewErrFieldMalformed(field)
+	}
+
+	return ships, nil
+}
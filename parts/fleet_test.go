@@ -0,0 +1,123 @@
+package parts_test
+
+import (
+	"errors"
+	"github.com/kovansky/wp-battleships/parts"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestFillFleet(t *testing.T) {
+	sizes := []int{4, 3, 3, 2, 2, 2, 1, 1, 1, 1}
+
+	for seed := int64(0); seed < 20; seed++ {
+		ships, err := parts.FillFleet(map[string]parts.State{}, sizes, rand.New(rand.NewSource(seed)))
+		if err != nil {
+			t.Fatalf("Received unexpected error: %v", err)
+		}
+
+		if len(ships) != len(sizes) {
+			t.Fatalf("Incorrect ships count; expected: %d, got: %d", len(sizes), len(ships))
+		}
+
+		occupied := map[string]int{}
+		for i, ship := range ships {
+			for identifier := range ship.Ship() {
+				occupied[identifier] = i
+			}
+		}
+
+		for i, ship := range ships {
+			protected, _ := ship.Protected()
+			for identifier := range protected {
+				if other, ok := occupied[identifier]; ok && other != i {
+					t.Fatalf("Ships %v and %v are touching", ship.Ship(), ships[other].Ship())
+				}
+			}
+		}
+	}
+}
+
+func TestFillFleet_KeepsBlocked(t *testing.T) {
+	blocked := map[string]parts.State{}
+	for _, column := range "ABCDEFGH" {
+		for row := 1; row <= 10; row++ {
+			identifier, _ := parts.NumericToIdentifier(uint8(column-'A')*10 + uint8(row-1))
+			blocked[identifier] = parts.FieldProtected
+		}
+	}
+
+	ships, err := parts.FillFleet(blocked, []int{2, 1}, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatalf("Received unexpected error: %v", err)
+	}
+
+	for _, ship := range ships {
+		for identifier := range ship.Ship() {
+			if _, ok := blocked[identifier]; ok {
+				t.Fatalf("Ship placed on blocked field %s", identifier)
+			}
+		}
+	}
+}
+
+func TestFillFleet_NoRoom(t *testing.T) {
+	blocked := map[string]parts.State{}
+	for numeric := uint8(0); numeric < 100; numeric++ {
+		// Leave only A1-A3 free
+		if numeric < 3 {
+			continue
+		}
+
+		identifier, _ := parts.NumericToIdentifier(numeric)
+		blocked[identifier] = parts.FieldProtected
+	}
+
+	_, err := parts.FillFleet(blocked, []int{4, 1}, rand.New(rand.NewSource(1)))
+
+	var incomplete parts.ErrFleetIncomplete
+	if !errors.As(err, &incomplete) {
+		t.Fatalf("Expected ErrFleetIncomplete, got: %v", err)
+	}
+}
+
+func TestSplitFleet(t *testing.T) {
+	type tableData struct {
+		name     string
+		fields   []string
+		expected [][]string
+		wantErr  bool
+	}
+
+	table := []tableData{
+		{"Single ships", []string{"A1", "C1", "A3"}, [][]string{{"A1"}, {"A3"}, {"C1"}}, false},
+		{"Vertical and horizontal", []string{"B5", "B3", "B4", "D7", "F7", "E7"}, [][]string{{"B3", "B4", "B5"}, {"D7", "E7", "F7"}}, false},
+		{"Touching by a corner", []string{"A1", "B2"}, [][]string{{"A1"}, {"B2"}}, false},
+		{"Too long", []string{"A1", "A2", "A3", "A4", "A5"}, nil, true},
+		{"Outside of the board", []string{"A1", "K1"}, nil, true},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			ships, err := parts.SplitFleet(tt.fields)
+			if err != nil && !tt.wantErr {
+				t.Fatalf("Received unexpected error: %v", err)
+			} else if err != nil && tt.wantErr {
+				return
+			} else if tt.wantErr {
+				t.Fatalf("Expected error, got %d ships", len(ships))
+			}
+
+			if len(ships) != len(tt.expected) {
+				t.Fatalf("Incorrect ships count; expected: %d, got: %d", len(tt.expected), len(ships))
+			}
+
+			for i, ship := range ships {
+				if got := strings.Join(ship.Fields(), " "); got != strings.Join(tt.expected[i], " ") {
+					t.Fatalf("Incorrect ship %d; expected: %v, got: %v", i, tt.expected[i], ship.Fields())
+				}
+			}
+		})
+	}
+}
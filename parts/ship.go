@@ -1,5 +1,7 @@
 package parts
 
+import "sort"
+
 var AllowedSizes = map[int]interface{}{1: nil, 2: nil, 3: nil, 4: nil}
 
 type Ship struct {
@@ -72,6 +74,34 @@ func (s Ship) Ship() map[string]Field {
 	return s.ship
 }
 
+// Fields returns the identifiers of the ship's fields, from A1 towards J10, column by column.
+func (s Ship) Fields() []string {
+	fields := make([]Field, 0, len(s.ship))
+	for _, f := range s.ship {
+		fields = append(fields, f)
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].Numeric() < fields[j].Numeric()
+	})
+
+	identifiers := make([]string, len(fields))
+	for i, f := range fields {
+		identifiers[i] = f.String()
+	}
+
+	return identifiers
+}
+
+// Clone returns a deep copy of the ship. Ship.Add modifies the underlying map, so copies of the struct share state.
+func (s Ship) Clone() Ship {
+	clone := Ship{finished: s.finished, ship: make(map[string]Field, len(s.ship))}
+	for identifier, f := range s.ship {
+		clone.ship[identifier] = f
+	}
+
+	return clone
+}
+
 func (s Ship) Protected() (map[string]StatedField, error) {
 	protected := make(map[string]StatedField)
 
@@ -116,3 +146,107 @@ func (s Ship) IsValidSize() bool {
 	_, ok := AllowedSizes[s.Size()]
 	return ok
 }
+
+type Orientation int
+
+const (
+	Horizontal Orientation = iota
+	Vertical
+)
+
+func (o Orientation) Rotate() Orientation {
+	if o == Horizontal {
+		return Vertical
+	}
+
+	return Horizontal
+}
+
+func (o Orientation) String() string {
+	if o == Vertical {
+		return "vertical"
+	}
+
+	return "horizontal"
+}
+
+// NewShipAt builds a ship of given size starting at anchor. Horizontal ships extend towards J (east),
+// vertical ones extend towards row 1 (south), so the anchor is always the top-left field of the ship.
+func NewShipAt(anchor string, size int, orientation Orientation) (Ship, error) {
+	if _, ok := AllowedSizes[size]; !ok {
+		return Ship{}, NewErrShipSize(size)
+	}
+
+	f, err := NewField(anchor)
+	if err != nil {
+		return Ship{}, err
+	}
+
+	direction := "E"
+	if orientation == Vertical {
+		direction = "S"
+	}
+
+	ship := NewShip()
+	for i := 0; i < size; i++ {
+		if ship, err = ship.Add(f.String()); err != nil {
+			return Ship{}, err
+		}
+
+		if i == size-1 {
+			break
+		}
+
+		next, ok := f.Adjacent()[direction]
+		if !ok {
+			return Ship{}, NewErrFieldOutOfRange()
+		}
+
+		if f, err = NewField(next); err != nil {
+			return Ship{}, err
+		}
+	}
+
+	return ship, nil
+}
+
+// Orientation guesses the ship orientation from its fields. Single-field ships are always horizontal.
+func (s Ship) Orientation() Orientation {
+	var letter byte
+
+	for identifier := range s.ship {
+		if letter != 0 && identifier[0] != letter {
+			return Horizontal
+		}
+
+		letter = identifier[0]
+	}
+
+	if s.Size() > 1 {
+		return Vertical
+	}
+
+	return Horizontal
+}
+
+// Anchor returns the top-left field of the ship, as expected by NewShipAt.
+func (s Ship) Anchor() string {
+	var (
+		anchor Field
+		found  bool
+	)
+
+	for _, f := range s.ship {
+		if !found {
+			anchor, found = f, true
+			continue
+		}
+
+		// Horizontal ships are anchored at the lowest column, vertical ones at the highest row
+		if f.Numeric()/10 < anchor.Numeric()/10 || (f.Numeric()/10 == anchor.Numeric()/10 && f.Numeric()%10 > anchor.Numeric()%10) {
+			anchor = f
+		}
+	}
+
+	return anchor.String()
+}
@@ -0,0 +1,62 @@
+package parts_test
+
+import (
+	"github.com/kovansky/wp-battleships/parts"
+	"sort"
+	"testing"
+)
+
+func TestNewShipAt(t *testing.T) {
+	type tableData struct {
+		name        string
+		anchor      string
+		size        int
+		orientation parts.Orientation
+		expected    []string
+		wantErr     bool
+	}
+
+	table := []tableData{
+		{"Single A1", "A1", 1, parts.Horizontal, []string{"A1"}, false},
+		{"Horizontal C5", "C5", 3, parts.Horizontal, []string{"C5", "D5", "E5"}, false},
+		{"Vertical B10", "B10", 4, parts.Vertical, []string{"B10", "B7", "B8", "B9"}, false},
+		{"Horizontal out of board", "H1", 4, parts.Horizontal, nil, true},
+		{"Vertical out of board", "A2", 3, parts.Vertical, nil, true},
+		{"Wrong size", "A1", 5, parts.Horizontal, nil, true},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			ship, err := parts.NewShipAt(tt.anchor, tt.size, tt.orientation)
+			if err != nil && !tt.wantErr {
+				t.Fatalf("Received unexpected error: %v", err)
+			} else if err != nil && tt.wantErr {
+				return
+			} else if tt.wantErr {
+				t.Fatalf("Expected error, got ship %v", ship.Ship())
+			}
+
+			var got []string
+			for f := range ship.Ship() {
+				got = append(got, f)
+			}
+			sort.Strings(got)
+
+			if len(got) != len(tt.expected) {
+				t.Fatalf("Incorrect ship; expected: %v, got: %v", tt.expected, got)
+			}
+			for i := range got {
+				if got[i] != tt.expected[i] {
+					t.Fatalf("Incorrect ship; expected: %v, got: %v", tt.expected, got)
+				}
+			}
+
+			if ship.Anchor() != tt.anchor {
+				t.Fatalf("Incorrect anchor; expected: %s, got: %s", tt.anchor, ship.Anchor())
+			}
+			if ship.Orientation() != tt.orientation {
+				t.Fatalf("Incorrect orientation; expected: %s, got: %s", tt.orientation, ship.Orientation())
+			}
+		})
+	}
+}
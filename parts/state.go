@@ -10,6 +10,7 @@ const (
 	FieldProtected       = "protected"
 	FieldCorner          = "corner"
 	FieldPotential       = "potential"
+	FieldPreview         = "preview"
 )
 
 func (s State) Priority() int {
@@ -26,6 +27,8 @@ func (s State) Priority() int {
 		return 4
 	case FieldProtected:
 		return 6
+	case FieldPreview:
+		return 7
 	default:
 		return 1
 	}
@@ -0,0 +1,39 @@
+package battleships
+
+import (
+	"errors"
+	"time"
+)
+
+// Retry calls op until it succeeds or runs out of attempts, doubling the delay after each failure.
+// Errors which another attempt won't fix are returned right away. Returns the last error.
+//
+// Only idempotent requests may be retried: a request which reached the server, but whose response got lost,
+// is sent again.
+func Retry(attempts int, delay time.Duration, op func() error) error {
+	var err error
+
+	for i := 0; i < attempts; i++ {
+		if err = op(); err == nil || !Retryable(err) {
+			return err
+		}
+
+		if i < attempts-1 {
+			time.Sleep(delay)
+			delay *= 2
+		}
+	}
+
+	return err
+}
+
+// Retryable tells whether the request may succeed when it's sent again. That's the case for the network errors
+// and the failures of the server (5xx), but not when the server rejected the request (4xx).
+func Retryable(err error) bool {
+	var serverErr ServerError
+	if errors.As(err, &serverErr) {
+		return serverErr.StatusCode >= 500
+	}
+
+	return true
+}
@@ -0,0 +1,63 @@
+package battleships_test
+
+import (
+	"errors"
+	"fmt"
+	battleships "github.com/kovansky/wp-battleships"
+	"testing"
+	"time"
+)
+
+func TestRetry(t *testing.T) {
+	calls := 0
+	err := battleships.Retry(3, time.Millisecond, func() error {
+		calls++
+		if calls < 2 {
+			return errors.New("temporary")
+		}
+
+		return nil
+	})
+	if err != nil || calls != 2 {
+		t.Fatalf("Expected success after 2 calls, got %d calls and error: %v", calls, err)
+	}
+
+	calls = 0
+	err = battleships.Retry(3, time.Millisecond, func() error {
+		calls++
+		return errors.New("persistent")
+	})
+	if err == nil || calls != 3 {
+		t.Fatalf("Expected error after 3 calls, got %d calls and error: %v", calls, err)
+	}
+
+	calls = 0
+	err = battleships.Retry(3, time.Millisecond, func() error {
+		calls++
+		return battleships.ServerError{StatusCode: 400, Message: "Invalid board"}
+	})
+	if err == nil || calls != 1 {
+		t.Fatalf("Expected error after 1 call, got %d calls and error: %v", calls, err)
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		retryable bool
+	}{
+		{"network", errors.New("connection refused"), true},
+		{"server failure", battleships.ServerError{StatusCode: 500}, true},
+		{"bad request", battleships.ServerError{StatusCode: 400}, false},
+		{"wrapped", fmt.Errorf("firing: %w", battleships.ServerError{StatusCode: 404}), false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if retryable := battleships.Retryable(test.err); retryable != test.retryable {
+				t.Fatalf("Incorrect result for %v; expected: %v, got: %v", test.err, test.retryable, retryable)
+			}
+		})
+	}
+}
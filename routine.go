@@ -1,12 +1,57 @@
 package battleships
 
-type GameRoutines struct {
-	Lobby Routine
-	Game  Routine
-	Wait  Routine
-}
+import "time"
+
+type RoutineKind string
+
+const (
+	RoutineLobby RoutineKind = "lobby"
+	RoutineWait  RoutineKind = "wait"
+	RoutineGame  RoutineKind = "game"
+	// RoutineTheme watches the theme file, for as long as the application runs
+	RoutineTheme RoutineKind = "theme"
+)
 
 type Routine interface {
+	Kind() RoutineKind
 	Run()
 	Quit()
 }
+
+type RoutineState string
+
+const (
+	RoutineStarting RoutineState = "starting"
+	RoutineRunning  RoutineState = "running"
+	RoutineStopping RoutineState = "stopping"
+	RoutineStopped  RoutineState = "stopped"
+	// RoutineExited means the routine returned on its own, i.e. gave up after repeated errors
+	RoutineExited  RoutineState = "exited"
+	RoutineCrashed RoutineState = "crashed"
+)
+
+type RoutineStatus struct {
+	Kind    RoutineKind
+	State   RoutineState
+	Started time.Time
+	Stopped time.Time
+	// Starts counts how many times a routine of this kind has been started
+	Starts int
+	Err    error
+}
+
+// RoutineSupervisor owns the background routines. It keeps at most one routine of each kind running,
+// and makes sure the previous one has exited before its replacement starts.
+type RoutineSupervisor interface {
+	// Start stops the routine of the same kind, if there is one, and runs the new one.
+	Start(routine Routine)
+	Stop(kind RoutineKind)
+	StopAll()
+	// Wait blocks until all routines have exited, or the timeout has passed. Returns false on timeout.
+	Wait(timeout time.Duration) bool
+
+	Status() []RoutineStatus
+}
+
+// RoutinesUpdateMsg is sent when the state of any routine has changed.
+type RoutinesUpdateMsg struct{}
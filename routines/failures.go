@@ -0,0 +1,50 @@
+package routines
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kovansky/wp-battleships/tui"
+	"time"
+)
+
+const (
+	// maxFailures is the count of consecutive errors, after which the routine gives up
+	maxFailures = 5
+	// initialBackoff is the pause after the first error, doubled with every next one
+	initialBackoff = time.Second
+)
+
+// failures tracks consecutive errors of a routine and spaces out the next attempts.
+type failures struct {
+	send func(msg tea.Msg)
+
+	count int
+	until time.Time
+}
+
+// waiting tells if the routine should skip this tick, backing off after an error.
+func (f *failures) waiting() bool {
+	return time.Now().Before(f.until)
+}
+
+func (f *failures) reset() {
+	f.count = 0
+	f.until = time.Time{}
+}
+
+// report sends the error to the TUI. Returns false if the routine should give up, in which case
+// the error is reported as persistent and retry is offered to the player.
+func (f *failures) report(message string, err error, retry tea.Cmd) bool {
+	f.count++
+
+	if f.count >= maxFailures {
+		f.reset()
+
+		f.send(tui.ErrorMsg{Message: message, Err: err, Persistent: true, Retry: retry})
+		return false
+	}
+
+	f.until = time.Now().Add(initialBackoff << (f.count - 1))
+	f.send(tui.NotifyMsg{Severity: tui.SeverityWarning, Message: message + " (retrying): " + err.Error()})
+
+	return true
+}
@@ -10,56 +10,60 @@ import (
 )
 
 type Game struct {
-	log zerolog.Logger
+	log     zerolog.Logger
+	session *battleships.Session
 
-	theme battleships.Theme
+	// game is the one current at the start, the session may move on to the next game before the routine is stopped
+	game battleships.Game
 
 	duration time.Duration
 
 	quit chan struct{}
 }
 
-func CreateGame(ctx context.Context, duration time.Duration, theme battleships.Theme, quit chan struct{}) Game {
+func CreateGame(ctx context.Context, session *battleships.Session, duration time.Duration) Game {
 	log := ctx.Value(battleships.ContextKeyLog).(zerolog.Logger)
 
 	return Game{
 		log:      log,
+		session:  session,
+		game:     session.Game(),
 		duration: duration,
-		quit:     quit,
-		theme:    theme,
+		quit:     make(chan struct{}),
 	}
 }
 
+func (g Game) Kind() battleships.RoutineKind {
+	return battleships.RoutineGame
+}
+
 func (g Game) Run() {
+	failed := failures{send: g.session.Send}
+
+	// The game publishes the changes made by the updates, pass them on to the TUI
+	events, unsubscribe := g.game.Subscribe()
+	defer unsubscribe()
+
 	ticker := time.NewTicker(g.duration)
 	for {
 		select {
 		case <-ticker.C:
-			err := battleships.ServerClient.GameStatus(battleships.GameInstance)
-			if err != nil {
-				g.log.Fatal().Err(err).Msg("Couldn't update the game status")
+			if failed.waiting() {
+				continue
 			}
 
-			if battleships.GameInstance.GameStatus().Status == battleships.StatusGameInProgress && battleships.GameInstance.GameStatus().ShouldFire && (battleships.GameInstance.Opponent() == nil || battleships.GameInstance.Opponent().Name() == "") {
-				err = battleships.ServerClient.GameDesc(battleships.GameInstance)
-				if err != nil {
-					g.log.Fatal().Err(err).Msg("Couldn't update the game description")
+			if message, err := g.update(); err != nil {
+				if !failed.report(message, err, restart(g.session.Routines, g)) {
+					ticker.Stop()
+					return
 				}
 
-				playersInfo := fmt.Sprintf("%s %s %s\n"+
-					"%s %s %s",
-					g.theme.TextPrimary().Copy().Bold(true).Render("YOU"),
-					battleships.GameInstance.Player().Name(),
-					lipgloss.NewStyle().Italic(true).Render("("+battleships.GameInstance.Player().Description()+")"),
-					g.theme.TextPrimary().Copy().Bold(true).Render("ENEMY"),
-					battleships.GameInstance.Opponent().Name(),
-					lipgloss.NewStyle().Italic(true).Render("("+battleships.GameInstance.Opponent().Description()+")"),
-				)
-
-				battleships.ProgramMessage(battleships.PlayersUpdateMsg{PlayersInfo: playersInfo})
+				continue
 			}
 
-			battleships.ProgramMessage(battleships.GameUpdateMsg{})
+			failed.reset()
+		case event := <-events:
+			g.session.Send(event)
 		case <-g.quit:
 			ticker.Stop()
 			return
@@ -67,14 +71,42 @@ func (g Game) Run() {
 	}
 }
 
+// update refreshes the game status, returning the description of the failed step along with the error.
+func (g Game) update() (string, error) {
+	game := g.game
+
+	err := g.session.Client.GameStatus(game)
+	if err != nil {
+		return "Couldn't update the game status", err
+	}
+
+	if game.GameStatus().Status == battleships.StatusGameInProgress && game.GameStatus().ShouldFire && (game.Opponent() == nil || game.Opponent().Name() == "") {
+		err = g.session.Client.GameDesc(game)
+		if err != nil {
+			return "Couldn't update the game description", err
+		}
+
+		theme := g.session.Themes().Global
+		playersInfo := fmt.Sprintf("%s %s %s\n"+
+			"%s %s %s",
+			theme.TextPrimary().Copy().Bold(true).Render("YOU"),
+			game.Player().Name(),
+			lipgloss.NewStyle().Italic(true).Render("("+game.Player().Description()+")"),
+			theme.TextPrimary().Copy().Bold(true).Render("ENEMY"),
+			game.Opponent().Name(),
+			lipgloss.NewStyle().Italic(true).Render("("+game.Opponent().Description()+")"),
+		)
+
+		g.session.Send(battleships.PlayersUpdateMsg{PlayersInfo: playersInfo})
+	}
+
+	return "", nil
+}
+
 func (g Game) Quit() {
 	select {
 	case <-g.quit:
 	default:
-		if battleships.GameInstance != nil && battleships.GameInstance.Key() != "" {
-			_ = battleships.ServerClient.Abandon(battleships.GameInstance)
-		}
-
 		close(g.quit)
 	}
 }
@@ -8,34 +8,52 @@ import (
 )
 
 type Lobby struct {
-	log zerolog.Logger
+	log     zerolog.Logger
+	session *battleships.Session
 
 	duration time.Duration
 
 	quit chan struct{}
 }
 
-func CreateLobby(ctx context.Context, duration time.Duration, quit chan struct{}) Lobby {
+func CreateLobby(ctx context.Context, session *battleships.Session, duration time.Duration) Lobby {
 	log := ctx.Value(battleships.ContextKeyLog).(zerolog.Logger)
 
 	return Lobby{
 		log:      log,
+		session:  session,
 		duration: duration,
-		quit:     quit,
+		quit:     make(chan struct{}),
 	}
 }
 
+func (l Lobby) Kind() battleships.RoutineKind {
+	return battleships.RoutineLobby
+}
+
 func (l Lobby) Run() {
+	failed := failures{send: l.session.Send}
+
 	ticker := time.NewTicker(l.duration)
 	for {
 		select {
 		case <-ticker.C:
-			players, err := battleships.ServerClient.ListPlayers()
+			if failed.waiting() {
+				continue
+			}
+
+			players, err := l.session.Client.ListPlayers()
 			if err != nil {
-				l.log.Fatal().Err(err).Msg("Couldn't list players")
+				if !failed.report("Couldn't list players", err, restart(l.session.Routines, l)) {
+					ticker.Stop()
+					return
+				}
+
+				continue
 			}
 
-			battleships.ProgramMessage(battleships.PlayersListMsg{Players: players})
+			failed.reset()
+			l.session.Send(battleships.PlayersListMsg{Players: players})
 		case <-l.quit:
 			ticker.Stop()
 			return
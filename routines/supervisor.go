@@ -0,0 +1,204 @@
+package routines
+
+import (
+	"context"
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	battleships "github.com/kovansky/wp-battleships"
+	"github.com/kovansky/wp-battleships/tui"
+	"github.com/rs/zerolog"
+	"sort"
+	"sync"
+	"time"
+)
+
+var _ battleships.RoutineSupervisor = (*Supervisor)(nil)
+
+type Supervisor struct {
+	log  zerolog.Logger
+	send func(msg tea.Msg)
+
+	mu       sync.Mutex
+	routines map[battleships.RoutineKind]*supervised
+	starts   map[battleships.RoutineKind]int
+}
+
+type supervised struct {
+	routine battleships.Routine
+	status  battleships.RoutineStatus
+
+	stopOnce sync.Once
+	stopped  bool
+	done     chan struct{}
+}
+
+// NewSupervisor creates the supervisor, which reports to the TUI using send.
+func NewSupervisor(ctx context.Context, send func(msg tea.Msg)) *Supervisor {
+	log := ctx.Value(battleships.ContextKeyLog).(zerolog.Logger)
+
+	return &Supervisor{
+		log:      log,
+		send:     send,
+		routines: make(map[battleships.RoutineKind]*supervised),
+		starts:   make(map[battleships.RoutineKind]int),
+	}
+}
+
+func (s *Supervisor) Start(routine battleships.Routine) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kind := routine.Kind()
+
+	previous := s.routines[kind]
+	if previous != nil {
+		s.stop(previous)
+	}
+
+	s.starts[kind]++
+
+	entry := &supervised{
+		routine: routine,
+		status: battleships.RoutineStatus{
+			Kind:   kind,
+			State:  battleships.RoutineStarting,
+			Starts: s.starts[kind],
+		},
+		done: make(chan struct{}),
+	}
+	s.routines[kind] = entry
+
+	go s.run(entry, previous)
+}
+
+func (s *Supervisor) Stop(kind battleships.RoutineKind) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entry, ok := s.routines[kind]; ok {
+		s.stop(entry)
+	}
+}
+
+func (s *Supervisor) StopAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, entry := range s.routines {
+		s.stop(entry)
+	}
+}
+
+func (s *Supervisor) Wait(timeout time.Duration) bool {
+	s.mu.Lock()
+	var done []chan struct{}
+	for _, entry := range s.routines {
+		done = append(done, entry.done)
+	}
+	s.mu.Unlock()
+
+	deadline := time.After(timeout)
+	for _, ch := range done {
+		select {
+		case <-ch:
+		case <-deadline:
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s *Supervisor) Status() []battleships.RoutineStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := make([]battleships.RoutineStatus, 0, len(s.routines))
+	for _, entry := range s.routines {
+		statuses = append(statuses, entry.status)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Kind < statuses[j].Kind
+	})
+
+	return statuses
+}
+
+// stop asks the routine to quit. Has to be called with the lock held.
+func (s *Supervisor) stop(entry *supervised) {
+	entry.stopOnce.Do(func() {
+		entry.stopped = true
+
+		switch entry.status.State {
+		case battleships.RoutineStarting, battleships.RoutineRunning:
+			entry.status.State = battleships.RoutineStopping
+			entry.routine.Quit()
+		}
+	})
+}
+
+func (s *Supervisor) run(entry *supervised, previous *supervised) {
+	defer close(entry.done)
+
+	// Only one routine of a kind may run at once, so let the previous one finish first
+	if previous != nil {
+		<-previous.done
+	}
+
+	s.mu.Lock()
+	if entry.stopped {
+		entry.status.State = battleships.RoutineStopped
+		entry.status.Stopped = time.Now()
+		s.mu.Unlock()
+		return
+	}
+	entry.status.State = battleships.RoutineRunning
+	entry.status.Started = time.Now()
+	s.mu.Unlock()
+
+	s.notify()
+
+	defer func() {
+		crash := recover()
+
+		s.mu.Lock()
+		entry.status.Stopped = time.Now()
+		switch {
+		case crash != nil:
+			entry.status.State = battleships.RoutineCrashed
+			entry.status.Err = fmt.Errorf("%v", crash)
+		case entry.stopped:
+			entry.status.State = battleships.RoutineStopped
+		default:
+			entry.status.State = battleships.RoutineExited
+		}
+		status := entry.status
+		s.mu.Unlock()
+
+		if crash != nil {
+			s.send(tui.ErrorMsg{
+				Message:    fmt.Sprintf("The %s routine has crashed", status.Kind),
+				Err:        status.Err,
+				Persistent: true,
+				Retry:      restart(s, entry.routine),
+			})
+		}
+
+		s.notify()
+	}()
+
+	entry.routine.Run()
+}
+
+func (s *Supervisor) notify() {
+	s.send(battleships.RoutinesUpdateMsg{})
+}
+
+// restart returns the command running the routine again, after it gave up or crashed.
+func restart(supervisor battleships.RoutineSupervisor, routine battleships.Routine) tea.Cmd {
+	return func() tea.Msg {
+		supervisor.Start(routine)
+		return nil
+	}
+}
@@ -0,0 +1,169 @@
+package routines_test
+
+import (
+	"context"
+	tea "github.com/charmbracelet/bubbletea"
+	battleships "github.com/kovansky/wp-battleships"
+	"github.com/kovansky/wp-battleships/routines"
+	"github.com/kovansky/wp-battleships/tui"
+	"github.com/rs/zerolog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeRoutine runs until it's told to quit, or panics right away if crash is set.
+type fakeRoutine struct {
+	kind    battleships.RoutineKind
+	crash   bool
+	running *int32
+	overlap *int32
+	quit    chan struct{}
+}
+
+func newFakeRoutine(kind battleships.RoutineKind, running, overlap *int32) fakeRoutine {
+	return fakeRoutine{kind: kind, running: running, overlap: overlap, quit: make(chan struct{})}
+}
+
+func (f fakeRoutine) Kind() battleships.RoutineKind {
+	return f.kind
+}
+
+func (f fakeRoutine) Run() {
+	if atomic.AddInt32(f.running, 1) > 1 {
+		atomic.AddInt32(f.overlap, 1)
+	}
+	defer atomic.AddInt32(f.running, -1)
+
+	if f.crash {
+		panic("boom")
+	}
+
+	<-f.quit
+	// Give a replacement started too early a chance to overlap
+	time.Sleep(10 * time.Millisecond)
+}
+
+func (f fakeRoutine) Quit() {
+	close(f.quit)
+}
+
+func createSupervisor(t *testing.T) (*routines.Supervisor, func() []tea.Msg) {
+	t.Helper()
+
+	var (
+		mu       sync.Mutex
+		messages []tea.Msg
+	)
+
+	send := func(msg tea.Msg) {
+		mu.Lock()
+		defer mu.Unlock()
+		messages = append(messages, msg)
+	}
+
+	ctx := context.WithValue(context.Background(), battleships.ContextKeyLog, zerolog.Nop())
+	supervisor := routines.NewSupervisor(ctx, send)
+
+	return supervisor, func() []tea.Msg {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]tea.Msg(nil), messages...)
+	}
+}
+
+func TestSupervisor_OneOfKind(t *testing.T) {
+	t.Parallel()
+
+	supervisor, _ := createSupervisor(t)
+
+	var running, overlap int32
+	for i := 0; i < 5; i++ {
+		supervisor.Start(newFakeRoutine(battleships.RoutineLobby, &running, &overlap))
+	}
+
+	supervisor.StopAll()
+	if !supervisor.Wait(time.Second) {
+		t.Fatalf("Routines did not stop in time")
+	}
+
+	if overlap != 0 {
+		t.Fatalf("Routines of the same kind overlapped %d times", overlap)
+	}
+
+	statuses := supervisor.Status()
+	if len(statuses) != 1 {
+		t.Fatalf("Expected one routine, got %d", len(statuses))
+	}
+	if statuses[0].State != battleships.RoutineStopped || statuses[0].Starts != 5 {
+		t.Fatalf("Unexpected status: %+v", statuses[0])
+	}
+}
+
+func TestSupervisor_Crash(t *testing.T) {
+	t.Parallel()
+
+	supervisor, messages := createSupervisor(t)
+
+	var running, overlap int32
+	routine := newFakeRoutine(battleships.RoutineGame, &running, &overlap)
+	routine.crash = true
+
+	supervisor.Start(routine)
+	if !supervisor.Wait(time.Second) {
+		t.Fatalf("Routine did not stop in time")
+	}
+
+	status := supervisor.Status()[0]
+	if status.State != battleships.RoutineCrashed || status.Err == nil {
+		t.Fatalf("Unexpected status: %+v", status)
+	}
+
+	var reported *tui.ErrorMsg
+	for _, msg := range messages() {
+		if errMsg, ok := msg.(tui.ErrorMsg); ok {
+			reported = &errMsg
+		}
+	}
+	if reported == nil || !reported.Persistent || reported.Retry == nil {
+		t.Fatalf("Expected the crash to be reported with a retry, got %+v", reported)
+	}
+
+	// Retrying runs the routine again
+	reported.Retry()
+	supervisor.Wait(time.Second)
+
+	if status = supervisor.Status()[0]; status.Starts != 2 {
+		t.Fatalf("Expected the routine to be restarted, got %+v", status)
+	}
+}
+
+func TestSupervisor_Stop(t *testing.T) {
+	t.Parallel()
+
+	supervisor, _ := createSupervisor(t)
+
+	var running, overlap int32
+	supervisor.Start(newFakeRoutine(battleships.RoutineLobby, &running, &overlap))
+	supervisor.Start(newFakeRoutine(battleships.RoutineWait, &running, &overlap))
+
+	supervisor.Stop(battleships.RoutineWait)
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		statuses := supervisor.Status()
+		if statuses[0].State == battleships.RoutineRunning && statuses[1].State == battleships.RoutineStopped {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Unexpected statuses: %+v", statuses)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	supervisor.StopAll()
+	if !supervisor.Wait(time.Second) {
+		t.Fatalf("Routines did not stop in time")
+	}
+}
@@ -0,0 +1,99 @@
+package routines
+
+import (
+	"context"
+	"github.com/fsnotify/fsnotify"
+	battleships "github.com/kovansky/wp-battleships"
+	"github.com/kovansky/wp-battleships/tui"
+	"github.com/kovansky/wp-battleships/tui/themes"
+	"github.com/rs/zerolog"
+	"path/filepath"
+	"time"
+)
+
+// reloadDelay gathers the burst of events of a single save into one reload.
+const reloadDelay = 100 * time.Millisecond
+
+// Theme applies the theme file again whenever it changes.
+type Theme struct {
+	log     zerolog.Logger
+	session *battleships.Session
+
+	// name is the theme as the player has given it, path is where the file is watched
+	name string
+	path string
+
+	quit chan struct{}
+}
+
+func CreateTheme(ctx context.Context, session *battleships.Session, path string) Theme {
+	log := ctx.Value(battleships.ContextKeyLog).(zerolog.Logger)
+
+	return Theme{
+		log:     log,
+		session: session,
+		name:    path,
+		path:    filepath.Clean(path),
+		quit:    make(chan struct{}),
+	}
+}
+
+func (t Theme) Kind() battleships.RoutineKind {
+	return battleships.RoutineTheme
+}
+
+func (t Theme) Run() {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		t.session.Send(tui.NotifyMsg{Severity: tui.SeverityError, Message: "Couldn't watch the theme file: " + err.Error()})
+		return
+	}
+	defer watcher.Close()
+
+	// Editors often save by replacing the file, which would end watching the file itself
+	if err = watcher.Add(filepath.Dir(t.path)); err != nil {
+		t.session.Send(tui.NotifyMsg{Severity: tui.SeverityError, Message: "Couldn't watch the theme file: " + err.Error()})
+		return
+	}
+
+	reload := time.NewTimer(reloadDelay)
+	reload.Stop()
+	defer reload.Stop()
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+
+			if filepath.Clean(event.Name) == t.path && (event.Has(fsnotify.Write) || event.Has(fsnotify.Create)) {
+				reload.Reset(reloadDelay)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+
+			t.log.Warn().Err(err).Str("path", t.path).Msg("Error while watching the theme file")
+		case <-reload.C:
+			gameThemes, err := themes.Load(t.path)
+			if err != nil {
+				t.session.Send(tui.NotifyMsg{Severity: tui.SeverityError, Message: "Couldn't reload the theme, keeping the old one: " + err.Error()})
+				continue
+			}
+
+			t.session.Send(battleships.ThemesChangeMsg{Name: t.name, Themes: gameThemes})
+		case <-t.quit:
+			return
+		}
+	}
+}
+
+func (t Theme) Quit() {
+	select {
+	case <-t.quit:
+	default:
+		close(t.quit)
+	}
+}
@@ -0,0 +1,71 @@
+package routines_test
+
+import (
+	"context"
+	tea "github.com/charmbracelet/bubbletea"
+	battleships "github.com/kovansky/wp-battleships"
+	"github.com/kovansky/wp-battleships/routines"
+	"github.com/kovansky/wp-battleships/tui"
+	"github.com/rs/zerolog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTheme(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "theme.toml")
+	if err := os.WriteFile(path, []byte("[player]\nhit = { char = \"*\" }"), 0o600); err != nil {
+		t.Fatalf("Received unexpected error: %v", err)
+	}
+
+	messages := make(chan tea.Msg, 10)
+	session := battleships.NewSession(nil, battleships.GameThemes{}, battleships.Settings{Theme: path})
+	session.Send = func(msg tea.Msg) { messages <- msg }
+
+	ctx := context.WithValue(context.Background(), battleships.ContextKeyLog, zerolog.Nop())
+	routine := routines.CreateTheme(ctx, session, path)
+	go routine.Run()
+	defer routine.Quit()
+
+	// Give the watcher a moment to start
+	time.Sleep(50 * time.Millisecond)
+
+	tests := []struct {
+		name    string
+		content string
+		valid   bool
+	}{
+		{"valid", "[player]\nhit = { char = \"+\" }", true},
+		{"broken", "[player]\nhit = { char = \"++\" }", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := os.WriteFile(path, []byte(test.content), 0o600); err != nil {
+				t.Fatalf("Received unexpected error: %v", err)
+			}
+
+			select {
+			case msg := <-messages:
+				switch msg := msg.(type) {
+				case battleships.ThemesChangeMsg:
+					if !test.valid {
+						t.Fatalf("Expected the broken theme to be reported")
+					}
+					if msg.Name != path {
+						t.Fatalf("Received themes of %q, expected %q", msg.Name, path)
+					}
+				case tui.NotifyMsg:
+					if test.valid || msg.Severity != tui.SeverityError {
+						t.Fatalf("Received unexpected notification: %s", msg.Message)
+					}
+				default:
+					t.Fatalf("Received unexpected message %T", msg)
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatalf("The theme has not been reloaded")
+			}
+		})
+	}
+}
@@ -12,76 +12,64 @@ import (
 )
 
 type Wait struct {
-	log zerolog.Logger
+	log     zerolog.Logger
+	session *battleships.Session
 
 	statusDuration  time.Duration
 	refreshDuration time.Duration
-	theme           battleships.Theme
 
 	quit chan struct{}
 }
 
-func CreateWait(ctx context.Context, statusDuration time.Duration, refreshDuration time.Duration, quit chan struct{}) Wait {
+func CreateWait(ctx context.Context, session *battleships.Session, statusDuration time.Duration, refreshDuration time.Duration) Wait {
 	log := ctx.Value(battleships.ContextKeyLog).(zerolog.Logger)
 
 	return Wait{
 		log:             log,
+		session:         session,
 		statusDuration:  statusDuration,
 		refreshDuration: refreshDuration,
-		theme:           battleships.Themes.Global,
-		quit:            quit,
+		quit:            make(chan struct{}),
 	}
 }
 
+func (w Wait) Kind() battleships.RoutineKind {
+	return battleships.RoutineWait
+}
+
 func (w Wait) Run() {
+	failed := failures{send: w.session.Send}
+
 	statusTicker := time.NewTicker(w.statusDuration)
 	refreshTicker := time.NewTicker(w.refreshDuration)
 	for {
 		select {
 		case <-statusTicker.C:
-			err := battleships.ServerClient.GameStatus(battleships.GameInstance)
-			if err != nil {
-				w.log.Fatal().Err(err).Msg("Could not update game status")
+			if failed.waiting() {
+				continue
 			}
 
-			if battleships.GameInstance.GameStatus().Status == battleships.StatusGameInProgress {
-				err = battleships.ServerClient.UpdateBoard(battleships.GameInstance)
-				if err != nil {
-					w.log.Fatal().Err(err).Msg("Couldn't update the game board")
-				}
-				err = battleships.ServerClient.GameDesc(battleships.GameInstance)
-				if err != nil {
-					w.log.Fatal().Err(err).Msg("Couldn't update the game status")
+			if message, err := w.updateStatus(); err != nil {
+				if !failed.report(message, err, restart(w.session.Routines, w)) {
+					statusTicker.Stop()
+					refreshTicker.Stop()
+					return
 				}
 
-				playersInfo := fmt.Sprintf("%s %s %s\n"+
-					"%s %s %s",
-					w.theme.TextPrimary().Copy().Bold(true).Render("YOU"),
-					battleships.GameInstance.Player().Name(),
-					lipgloss.NewStyle().Italic(true).Render("("+battleships.GameInstance.Player().Description()+")"),
-					w.theme.TextPrimary().Copy().Bold(true).Render("ENEMY"),
-					battleships.GameInstance.Opponent().Name(),
-					lipgloss.NewStyle().Italic(true).Render("("+battleships.GameInstance.Opponent().Description()+")"),
-				)
-
-				gameBoard := board.InitFull(battleships.GameInstance, battleships.Themes.Player, battleships.Themes.Enemy, battleships.Themes.Global, playersInfo)
-
-				battleships.ProgramMessage(tui.ApplicationStageChangeMsg{
-					From:  tui.StageWait,
-					Stage: tui.StageGame,
-					Model: gameBoard,
-				})
+				continue
 			}
+
+			failed.reset()
 		case <-refreshTicker.C:
-			err := battleships.ServerClient.Refresh(battleships.GameInstance)
-			if err != nil {
-				statusErr := battleships.ServerClient.GameStatus(battleships.GameInstance)
-				if statusErr != nil {
-					w.log.Fatal().Err(statusErr).Msg("Could not update game status")
-				}
+			if failed.waiting() {
+				continue
+			}
 
-				if battleships.GameInstance.GameStatus().Status != battleships.StatusGameInProgress {
-					w.log.Fatal().Err(err).Msg("Couldn't refresh game")
+			if message, err := w.refresh(); err != nil {
+				if !failed.report(message, err, restart(w.session.Routines, w)) {
+					statusTicker.Stop()
+					refreshTicker.Stop()
+					return
 				}
 			}
 		case <-w.quit:
@@ -92,6 +80,73 @@ func (w Wait) Run() {
 	}
 }
 
+// updateStatus checks if the game has started, and switches to the game board if so.
+// Returns the description of the failed step along with the error.
+func (w Wait) updateStatus() (string, error) {
+	game := w.session.Game()
+
+	err := w.session.Client.GameStatus(game)
+	if err != nil {
+		return "Could not update game status", err
+	}
+
+	if game.GameStatus().Status != battleships.StatusGameInProgress {
+		return "", nil
+	}
+
+	err = w.session.Client.UpdateBoard(game)
+	if err != nil {
+		return "Couldn't update the game board", err
+	}
+	err = w.session.Client.GameDesc(game)
+	if err != nil {
+		return "Couldn't update the game description", err
+	}
+
+	theme := w.session.Themes().Global
+	playersInfo := fmt.Sprintf("%s %s %s\n"+
+		"%s %s %s",
+		theme.TextPrimary().Copy().Bold(true).Render("YOU"),
+		game.Player().Name(),
+		lipgloss.NewStyle().Italic(true).Render("("+game.Player().Description()+")"),
+		theme.TextPrimary().Copy().Bold(true).Render("ENEMY"),
+		game.Opponent().Name(),
+		lipgloss.NewStyle().Italic(true).Render("("+game.Opponent().Description()+")"),
+	)
+
+	gameBoard := board.InitFull(w.session, playersInfo)
+
+	w.session.Send(tui.ApplicationStageChangeMsg{
+		From:  tui.StageWait,
+		Stage: tui.StageGame,
+		Model: gameBoard,
+	})
+
+	return "", nil
+}
+
+// refresh keeps the waiting game alive on the server.
+func (w Wait) refresh() (string, error) {
+	game := w.session.Game()
+
+	err := w.session.Client.Refresh(game)
+	if err == nil {
+		return "", nil
+	}
+
+	// Refresh fails once the game has started, which is fine
+	statusErr := w.session.Client.GameStatus(game)
+	if statusErr != nil {
+		return "Could not update game status", statusErr
+	}
+
+	if game.GameStatus().Status != battleships.StatusGameInProgress {
+		return "Couldn't refresh game", err
+	}
+
+	return "", nil
+}
+
 func (w Wait) Quit() {
 	select {
 	case <-w.quit:
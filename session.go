@@ -0,0 +1,151 @@
+package battleships
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"sync"
+	"time"
+)
+
+// Session holds the state of a single player's run of the application: the server client, the current game,
+// the background routines and the way to reach the TUI. Sessions don't share any state, so there may be
+// more of them in one process.
+type Session struct {
+	Client   Client
+	Routines RoutineSupervisor
+	Settings Settings
+	Keys     KeyMap
+
+	// Send delivers the message to the TUI program of the session
+	Send func(msg tea.Msg)
+
+	mu     sync.RWMutex
+	game   Game
+	themes GameThemes
+	player PlayerData
+
+	// abandoned holds the keys of the games given up, abandoning tracks the requests still running
+	abandoned  map[string]bool
+	abandoning sync.WaitGroup
+}
+
+type PlayerData struct {
+	Nick        string
+	Description string
+	Board       []string
+	PlayMode    PlayMode
+}
+
+// GamePost describes a new game of the player, with the board set up before, if there is one.
+func (p PlayerData) GamePost() GamePost {
+	return GamePost{
+		Coords: p.Board,
+		Desc:   p.Description,
+		Nick:   p.Nick,
+	}
+}
+
+func NewSession(client Client, themes GameThemes, settings Settings) *Session {
+	return &Session{
+		Client:    client,
+		Settings:  settings,
+		Keys:      DefaultKeyMap(),
+		Send:      func(tea.Msg) {},
+		abandoned: make(map[string]bool),
+		themes:    themes,
+	}
+}
+
+// Themes returns the current look of the application. The player may switch it while the routines are rendering.
+func (s *Session) Themes() GameThemes {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.themes
+}
+
+func (s *Session) SetThemes(themes GameThemes) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.themes = themes
+}
+
+// Player returns what the player has entered. The TUI changes it while the commands and routines are reading it.
+func (s *Session) Player() PlayerData {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.player
+}
+
+func (s *Session) SetPlayer(player PlayerData) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.player = player
+}
+
+// Game returns the current game. It may be replaced by the TUI while the routines are reading it.
+func (s *Session) Game() Game {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.game
+}
+
+func (s *Session) SetGame(game Game) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.game = game
+}
+
+// Abandon gives up the game on the server, unless it has ended or was given up already.
+// The request runs in the background, Close waits for it.
+func (s *Session) Abandon(game Game) {
+	if game == nil || game.Key() == "" || game.GameStatus().Status == StatusEnded {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.abandoned[game.Key()] {
+		return
+	}
+	s.abandoned[game.Key()] = true
+
+	s.abandoning.Add(1)
+	go func() {
+		defer s.abandoning.Done()
+		_ = s.Client.Abandon(game)
+	}()
+}
+
+// Close ends the session: abandons the current game, stops the routines and waits for both, at most until
+// the timeout. Returns false if they didn't finish in time.
+func (s *Session) Close(timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+
+	s.Abandon(s.Game())
+
+	if s.Routines != nil {
+		s.Routines.StopAll()
+		if !s.Routines.Wait(timeout) {
+			return false
+		}
+	}
+
+	abandoned := make(chan struct{})
+	go func() {
+		s.abandoning.Wait()
+		close(abandoned)
+	}()
+
+	select {
+	case <-abandoned:
+		return true
+	case <-time.After(time.Until(deadline)):
+		return false
+	}
+}
@@ -0,0 +1,75 @@
+package battleships_test
+
+import (
+	battleships "github.com/kovansky/wp-battleships"
+	"github.com/kovansky/wp-battleships/ships"
+	"github.com/rs/zerolog"
+	"sync"
+	"testing"
+	"time"
+)
+
+// abandonClient records the abandoned games, taking the given time for each.
+type abandonClient struct {
+	battleships.Client
+
+	delay time.Duration
+
+	mu        sync.Mutex
+	abandoned []string
+}
+
+func (c *abandonClient) Abandon(game battleships.Game) error {
+	time.Sleep(c.delay)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.abandoned = append(c.abandoned, game.Key())
+
+	return nil
+}
+
+func newGame(key string, status battleships.Status) battleships.Game {
+	log := zerolog.Nop()
+
+	game := ships.NewGame(key, &log)
+	game.SetGameStatus(battleships.GameStatus{Status: status})
+
+	return game
+}
+
+func TestSession_Close(t *testing.T) {
+	client := &abandonClient{delay: 10 * time.Millisecond}
+	session := battleships.NewSession(client, battleships.GameThemes{}, battleships.DefaultSettings())
+
+	// Games are abandoned once, and only if they haven't ended
+	session.Abandon(newGame("left", battleships.StatusGameInProgress))
+	session.Abandon(newGame("ended", battleships.StatusEnded))
+
+	current := newGame("current", battleships.StatusWaiting)
+	session.SetGame(current)
+	session.Abandon(current)
+
+	if !session.Close(time.Second) {
+		t.Fatalf("Session did not close in time")
+	}
+
+	if len(client.abandoned) != 2 {
+		t.Fatalf("Incorrect games abandoned; expected: [left current], got: %v", client.abandoned)
+	}
+}
+
+func TestSession_CloseTimeout(t *testing.T) {
+	client := &abandonClient{delay: time.Second}
+	session := battleships.NewSession(client, battleships.GameThemes{}, battleships.DefaultSettings())
+	session.SetGame(newGame("current", battleships.StatusGameInProgress))
+
+	start := time.Now()
+	if session.Close(20 * time.Millisecond) {
+		t.Fatalf("Session should not close before the game is abandoned")
+	}
+
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("Close did not respect the timeout, took %s", elapsed)
+	}
+}
@@ -0,0 +1,41 @@
+package battleships
+
+// Settings are the user preferences, loaded from the settings file.
+type Settings struct {
+	// Theme is the name of a built-in theme, or the path to a theme file
+	Theme string        `json:"theme"`
+	Timer TimerSettings `json:"timer"`
+	Game  GameSettings  `json:"game"`
+	Keys  KeySettings   `json:"keys"`
+}
+
+type TimerSettings struct {
+	// WarningThreshold is the number of seconds left in our turn, below which the timer warns the player
+	WarningThreshold int `json:"warning_threshold"`
+	// Bell rings the terminal bell when the warning threshold is reached
+	Bell bool `json:"bell"`
+	// AutoFire fires the best available guess right before the turn times out
+	AutoFire bool `json:"auto_fire"`
+}
+
+type GameSettings struct {
+	// AutoStart starts the next game on its own after the game ends, the same way the last one was started
+	AutoStart bool `json:"auto_start"`
+	// Animations plays short animations of the shots and of the result of the game
+	Animations bool `json:"animations"`
+}
+
+func DefaultSettings() Settings {
+	return Settings{
+		Theme: "classic",
+		Timer: TimerSettings{
+			WarningThreshold: 10,
+			Bell:             true,
+			AutoFire:         false,
+		},
+		Game: GameSettings{
+			AutoStart:  false,
+			Animations: true,
+		},
+	}
+}
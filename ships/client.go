@@ -131,21 +131,7 @@ func (c *Client) GameStatus(game battleships.Game) error {
 	}
 
 	game.SetGameStatus(status)
-
-	board := game.Board()
-
-	if board != nil {
-		for _, shot := range parsed.OppShots {
-			if _, ok := board[shot]; ok &&
-				(board[shot] == battleships.FieldStateShip ||
-					board[shot] == battleships.FieldStateHit) {
-				board[shot] = battleships.FieldStateHit
-			} else {
-				board[shot] = battleships.FieldStateMiss
-			}
-		}
-		game.SetBoard(board)
-	}
+	game.RecordOpponentShots(parsed.OppShots)
 
 	return nil
 }
@@ -318,7 +304,7 @@ func (c *Client) request(method, endpoint string, key string, body []byte) ([]by
 					return nil
 				}
 
-				errMsg := fmt.Sprintf("Server returned code %d", res.StatusCode)
+				serverErr := battleships.ServerError{StatusCode: res.StatusCode}
 
 				var parsed map[string]interface{}
 				if err = json.NewDecoder(res.Body).Decode(&parsed); err != nil {
@@ -328,10 +314,10 @@ func (c *Client) request(method, endpoint string, key string, body []byte) ([]by
 				}
 
 				if message, ok := parsed["message"]; ok {
-					errMsg = fmt.Sprintf("Server returned code %d. Message: %v", res.StatusCode, message)
+					serverErr.Message = fmt.Sprint(message)
 				}
 
-				return errors.New(errMsg)
+				return serverErr
 			}
 			finished = true
 
@@ -3,34 +3,58 @@ package ships
 import (
 	battleships "github.com/kovansky/wp-battleships"
 	"github.com/rs/zerolog"
+	"sync"
+	"time"
 )
 
+// eventBuffer is the capacity of a subscription. Subscribers falling further behind lose events.
+const eventBuffer = 64
+
 var _ battleships.Game = (*Game)(nil)
 
 type Game struct {
 	key string
 
+	mu sync.RWMutex
+
 	opponent battleships.Player
 	player   battleships.Player
 
 	status        battleships.GameStatus
 	board         map[string]battleships.FieldState
 	opponentBoard map[string]battleships.FieldState
+	annotations   map[string]battleships.Annotation
+	history       []battleships.Shot
+	fleet         [][]string
 
 	stats *battleships.Statistics
 
+	subscribers    map[int]chan battleships.GameEvent
+	nextSubscriber int
+
 	log *zerolog.Logger
 }
 
 func NewGame(key string, log *zerolog.Logger) battleships.Game {
-	return &Game{key: key, log: log, stats: battleships.NewStatistics()}
+	return &Game{
+		key:         key,
+		log:         log,
+		stats:       battleships.NewStatistics(),
+		subscribers: make(map[int]chan battleships.GameEvent),
+	}
 }
 
 func (g *Game) SetPlayer(player battleships.Player) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
 	g.player = player
 }
 
 func (g *Game) Player() battleships.Player {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
 	return g.player
 }
 
@@ -39,37 +63,270 @@ func (g *Game) Key() string {
 }
 
 func (g *Game) SetBoard(board map[string]battleships.FieldState) {
-	g.board = board
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.board = copyMap(board)
 }
 
 func (g *Game) Board() map[string]battleships.FieldState {
-	return g.board
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return copyMap(g.board)
 }
 
 func (g *Game) SetOpponentBoard(board map[string]battleships.FieldState) {
-	g.opponentBoard = board
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.opponentBoard = copyMap(board)
 }
 
 func (g *Game) OpponentBoard() map[string]battleships.FieldState {
-	return g.opponentBoard
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return copyMap(g.opponentBoard)
+}
+
+func (g *Game) RecordShot(field string, result battleships.FieldState) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.opponentBoard == nil {
+		g.opponentBoard = make(map[string]battleships.FieldState)
+	}
+	g.opponentBoard[field] = result
+	g.history = append(g.history, battleships.Shot{Field: field, Result: result, Time: time.Now()})
+
+	g.stats.IncrementShots()
+	switch result {
+	case battleships.FieldStateHit:
+		g.stats.IncrementHits()
+	case battleships.FieldStateSunk:
+		g.stats.IncrementHits()
+		g.stats.IncrementSunk()
+	}
+
+	g.publish(battleships.ShotFired{Field: field, Result: result})
+}
+
+func (g *Game) RecordOpponentShots(fields []string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	// Our fleet is not known yet, so there is nothing to shoot at
+	if g.board == nil {
+		return
+	}
+
+	for _, field := range fields {
+		state, ok := g.board[field]
+
+		// The server lists all the shots every time, this one has been recorded already
+		if ok && state != battleships.FieldStateShip {
+			continue
+		}
+
+		var result battleships.FieldState = battleships.FieldStateMiss
+		if ok {
+			result = battleships.FieldStateHit
+		}
+
+		g.board[field] = result
+		if result == battleships.FieldStateHit && g.sink(field) {
+			result = battleships.FieldStateSunk
+		}
+
+		g.history = append(g.history, battleships.Shot{Opponent: true, Field: field, Result: result, Time: time.Now()})
+		g.publish(battleships.ShotReceived{Field: field, Result: result})
+	}
+}
+
+func (g *Game) History() []battleships.Shot {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return append([]battleships.Shot(nil), g.history...)
+}
+
+func (g *Game) SetFleet(fleet [][]string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.fleet = make([][]string, len(fleet))
+	for i, ship := range fleet {
+		g.fleet[i] = append([]string(nil), ship...)
+
+		// The ship may have been hit before we knew the fleet
+		if len(ship) > 0 {
+			g.sink(ship[0])
+		}
+	}
+}
+
+func (g *Game) Fleet() []battleships.ShipDamage {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	fleet := make([]battleships.ShipDamage, len(g.fleet))
+	for i, ship := range g.fleet {
+		fleet[i].Fields = append([]string(nil), ship...)
+
+		for _, field := range ship {
+			if state := g.board[field]; state == battleships.FieldStateHit || state == battleships.FieldStateSunk {
+				fleet[i].Hits++
+			}
+		}
+	}
+
+	return fleet
+}
+
+// sink marks the ship with the field as sunk, if all of its fields have been hit. Returns true if it has sunk.
+// Has to be called with the lock held.
+func (g *Game) sink(field string) bool {
+	for _, ship := range g.fleet {
+		if !contains(ship, field) {
+			continue
+		}
+
+		for _, f := range ship {
+			if state := g.board[f]; state != battleships.FieldStateHit && state != battleships.FieldStateSunk {
+				return false
+			}
+		}
+
+		for _, f := range ship {
+			g.board[f] = battleships.FieldStateSunk
+		}
+
+		return true
+	}
+
+	return false
+}
+
+func (g *Game) SetAnnotations(annotations map[string]battleships.Annotation) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.annotations = copyMap(annotations)
+}
+
+func (g *Game) Annotations() map[string]battleships.Annotation {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return copyMap(g.annotations)
 }
 
 func (g *Game) SetOpponent(player battleships.Player) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	joined := (g.opponent == nil || g.opponent.Name() == "") && player != nil && player.Name() != ""
 	g.opponent = player
+
+	if joined {
+		g.publish(battleships.OpponentJoined{Opponent: player})
+	}
 }
 
 func (g *Game) Opponent() battleships.Player {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
 	return g.opponent
 }
 
 func (g *Game) SetGameStatus(status battleships.GameStatus) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	previous := g.status
 	g.status = status
+
+	switch status.Status {
+	case battleships.StatusGameInProgress:
+		if previous.Status != status.Status || previous.ShouldFire != status.ShouldFire {
+			g.publish(battleships.TurnChanged{ShouldFire: status.ShouldFire, Timer: status.Timer})
+		} else if previous.Timer != status.Timer {
+			g.publish(battleships.StatusChanged{Timer: status.Timer})
+		}
+	case battleships.StatusEnded:
+		if previous.Status != status.Status {
+			g.publish(battleships.GameEnded{Result: status.LastStatus})
+		}
+	}
 }
 
 func (g *Game) GameStatus() battleships.GameStatus {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
 	return g.status
 }
 
 func (g *Game) Statistics() *battleships.Statistics {
 	return g.stats
 }
+
+func (g *Game) Subscribe() (<-chan battleships.GameEvent, func()) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	id := g.nextSubscriber
+	g.nextSubscriber++
+
+	events := make(chan battleships.GameEvent, eventBuffer)
+	g.subscribers[id] = events
+
+	var once sync.Once
+	return events, func() {
+		once.Do(func() {
+			g.mu.Lock()
+			defer g.mu.Unlock()
+
+			delete(g.subscribers, id)
+			close(events)
+		})
+	}
+}
+
+// publish sends the event to all subscribers. Has to be called with the lock held.
+func (g *Game) publish(event battleships.GameEvent) {
+	for _, events := range g.subscribers {
+		select {
+		case events <- event:
+		default:
+			if g.log != nil {
+				g.log.Warn().Interface("event", event).Msg("Subscriber is not keeping up, dropping game event")
+			}
+		}
+	}
+}
+
+func contains(fields []string, field string) bool {
+	for _, f := range fields {
+		if f == field {
+			return true
+		}
+	}
+
+	return false
+}
+
+func copyMap[K comparable, V any](original map[K]V) map[K]V {
+	if original == nil {
+		return nil
+	}
+
+	clone := make(map[K]V, len(original))
+	for key, value := range original {
+		clone[key] = value
+	}
+
+	return clone
+}
@@ -0,0 +1,212 @@
+package ships_test
+
+import (
+	battleships "github.com/kovansky/wp-battleships"
+	"github.com/kovansky/wp-battleships/ships"
+	"github.com/rs/zerolog"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestGame_Simulated plays a game with the server updates and the player's shots coming from
+// separate goroutines, like the routines and the TUI do. Meant to be run with -race.
+func TestGame_Simulated(t *testing.T) {
+	log := zerolog.Nop()
+	game := ships.NewGame("key", &log)
+	game.SetBoard(map[string]battleships.FieldState{
+		"A1": battleships.FieldStateShip,
+		"A2": battleships.FieldStateShip,
+		"B5": battleships.FieldStateShip,
+	})
+
+	events, unsubscribe := game.Subscribe()
+
+	received := make(map[string]int)
+	collected := make(chan struct{})
+	go func() {
+		defer close(collected)
+
+		for event := range events {
+			switch event := event.(type) {
+			case battleships.ShotReceived:
+				received["received "+string(event.Result)]++
+			case battleships.ShotFired:
+				received["fired"]++
+			case battleships.TurnChanged:
+				received["turn"]++
+			case battleships.OpponentJoined:
+				received["joined"]++
+			case battleships.GameEnded:
+				received["ended "+string(event.Result)]++
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	// The server side: the opponent joins, and the turns pass with the opponent's shots
+	go func() {
+		defer wg.Done()
+
+		game.SetOpponent(ships.NewPlayer("opponent", ""))
+		opponentShots := []string{"A1", "C3", "A2", "J10"}
+
+		for turn := 0; turn < 8; turn++ {
+			game.SetGameStatus(battleships.GameStatus{
+				Status:     battleships.StatusGameInProgress,
+				ShouldFire: turn%2 == 0,
+				Timer:      60,
+			})
+
+			if turn%2 == 1 {
+				// The server lists all shots so far, every time
+				game.RecordOpponentShots(opponentShots[:turn/2+1])
+			}
+		}
+	}()
+
+	// The player side: fire and read the state for rendering
+	go func() {
+		defer wg.Done()
+
+		for _, field := range []string{"E1", "E2", "E3", "F7", "G7"} {
+			result := battleships.FieldState(battleships.FieldStateMiss)
+			if field == "F7" {
+				result = battleships.FieldStateHit
+			}
+
+			game.RecordShot(field, result)
+
+			_ = game.Board()
+			_ = game.OpponentBoard()
+			_ = game.GameStatus()
+			_ = game.Statistics().Hits()
+			_ = game.History()
+		}
+	}()
+
+	wg.Wait()
+	game.SetGameStatus(battleships.GameStatus{Status: battleships.StatusEnded, LastStatus: battleships.StatusWin})
+
+	unsubscribe()
+	<-collected
+
+	expected := map[string]int{
+		"received hit":  2,
+		"received miss": 2,
+		"fired":         5,
+		"turn":          8,
+		"joined":        1,
+		"ended win":     1,
+	}
+	for key, count := range expected {
+		if received[key] != count {
+			t.Fatalf("Incorrect count of %q events; expected: %d, got: %d", key, count, received[key])
+		}
+	}
+
+	if stats := game.Statistics(); stats.Shots() != 5 || stats.Hits() != 1 {
+		t.Fatalf("Incorrect statistics; got %d shots, %d hits", stats.Shots(), stats.Hits())
+	}
+
+	// The shots of both players are mixed, but each player's are in order
+	var fired, incoming []string
+	for _, shot := range game.History() {
+		if shot.Opponent {
+			incoming = append(incoming, shot.Field)
+		} else {
+			fired = append(fired, shot.Field)
+		}
+	}
+	if strings.Join(fired, " ") != "E1 E2 E3 F7 G7" || strings.Join(incoming, " ") != "A1 C3 A2 J10" {
+		t.Fatalf("Incorrect history; fired: %v, received: %v", fired, incoming)
+	}
+
+	board := game.Board()
+	if board["A1"] != battleships.FieldStateHit || board["J10"] != battleships.FieldStateMiss || board["B5"] != battleships.FieldStateShip {
+		t.Fatalf("Incorrect board: %v", board)
+	}
+}
+
+func TestGame_Fleet(t *testing.T) {
+	log := zerolog.Nop()
+	game := ships.NewGame("key", &log)
+	game.SetBoard(map[string]battleships.FieldState{
+		"A1": battleships.FieldStateShip,
+		"A2": battleships.FieldStateShip,
+		"C3": battleships.FieldStateShip,
+	})
+	game.SetFleet([][]string{{"A1", "A2"}, {"C3"}})
+
+	tests := []struct {
+		name     string
+		shots    []string
+		recorded int
+		result   battleships.FieldState
+		hits     []int
+	}{
+		{"miss", []string{"B1"}, 1, battleships.FieldStateMiss, []int{0, 0}},
+		{"hit", []string{"B1", "A1"}, 2, battleships.FieldStateHit, []int{1, 0}},
+		{"sunk", []string{"B1", "A1", "A2"}, 3, battleships.FieldStateSunk, []int{2, 0}},
+		{"listed again", []string{"B1", "A1", "A2"}, 3, battleships.FieldStateSunk, []int{2, 0}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			game.RecordOpponentShots(test.shots)
+
+			history := game.History()
+			if len(history) != test.recorded {
+				t.Fatalf("Incorrect count of shots; expected: %d, got: %d", test.recorded, len(history))
+			}
+			if last := history[len(history)-1]; last.Result != test.result {
+				t.Fatalf("Incorrect result of %s; expected: %s, got: %s", last.Field, test.result, last.Result)
+			}
+
+			for i, ship := range game.Fleet() {
+				if ship.Hits != test.hits[i] {
+					t.Fatalf("Incorrect hits of ship %v; expected: %d, got: %d", ship.Fields, test.hits[i], ship.Hits)
+				}
+				if ship.Sunk() != (ship.Hits == len(ship.Fields)) {
+					t.Fatalf("Incorrect state of ship %v", ship.Fields)
+				}
+			}
+		})
+	}
+
+	if board := game.Board(); board["A1"] != battleships.FieldStateSunk || board["A2"] != battleships.FieldStateSunk {
+		t.Fatalf("The ship should be marked as sunk: %v", board)
+	}
+}
+
+func TestGame_StatusChanged(t *testing.T) {
+	log := zerolog.Nop()
+	game := ships.NewGame("key", &log)
+
+	events, unsubscribe := game.Subscribe()
+	defer unsubscribe()
+
+	for _, timer := range []int{60, 55, 55, 50} {
+		game.SetGameStatus(battleships.GameStatus{Status: battleships.StatusGameInProgress, ShouldFire: true, Timer: timer})
+	}
+
+	// The turn begins, then only the changes of the timer are published
+	expected := []battleships.GameEvent{
+		battleships.TurnChanged{ShouldFire: true, Timer: 60},
+		battleships.StatusChanged{Timer: 55},
+		battleships.StatusChanged{Timer: 50},
+	}
+	for _, event := range expected {
+		if received := <-events; received != event {
+			t.Fatalf("Incorrect event; expected: %+v, got: %+v", event, received)
+		}
+	}
+
+	select {
+	case received := <-events:
+		t.Fatalf("Unexpected event: %+v", received)
+	default:
+	}
+}
@@ -1,6 +1,11 @@
 package battleships
 
+import "sync"
+
+// Statistics of our shots. It is safe for concurrent use.
 type Statistics struct {
+	mu sync.RWMutex
+
 	shots int
 	hits  int
 	sunk  int
@@ -15,37 +20,64 @@ func NewStatistics() *Statistics {
 }
 
 func (s *Statistics) Shots() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	return s.shots
 }
 
 func (s *Statistics) Hits() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	return s.hits
 }
 
 func (s *Statistics) Sunk() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	return s.sunk
 }
 
 func (s *Statistics) SetShots(count int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	s.shots = count
 }
 
 func (s *Statistics) SetHits(count int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	s.hits = count
 }
 
 func (s *Statistics) SetSunk(count int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	s.sunk = count
 }
 
 func (s *Statistics) IncrementShots() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	s.shots++
 }
 
 func (s *Statistics) IncrementHits() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	s.hits++
 }
 
 func (s *Statistics) IncrementSunk() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	s.sunk++
 }
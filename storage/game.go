@@ -0,0 +1,76 @@
+package storage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	battleships "github.com/kovansky/wp-battleships"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const appDirectory = "wp-battleships"
+
+// GameData holds the client-side state of a game, which the server does not know about.
+type GameData struct {
+	Annotations map[string]battleships.Annotation `json:"annotations,omitempty"`
+}
+
+// LoadGame reads the data saved for the game with given key. Missing data is not an error.
+func LoadGame(key string) (GameData, error) {
+	var data GameData
+
+	path, err := gamePath(key)
+	if err != nil {
+		return data, err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return data, nil
+		}
+
+		return data, err
+	}
+
+	err = json.Unmarshal(content, &data)
+
+	return data, err
+}
+
+func SaveGame(key string, data GameData) error {
+	path, err := gamePath(key)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	content, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, content, 0o600)
+}
+
+func gamePath(key string) (string, error) {
+	if key == "" {
+		return "", errors.New("game has no key")
+	}
+
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	// Keys are auth tokens - don't keep them in plain text in file names
+	hash := sha256.Sum256([]byte(key))
+
+	return filepath.Join(cache, appDirectory, "games", hex.EncodeToString(hash[:])+".json"), nil
+}
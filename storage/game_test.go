@@ -0,0 +1,38 @@
+package storage_test
+
+import (
+	battleships "github.com/kovansky/wp-battleships"
+	"github.com/kovansky/wp-battleships/storage"
+	"testing"
+)
+
+func TestGame_SaveLoad(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	empty, err := storage.LoadGame("missing")
+	if err != nil {
+		t.Fatalf("Received unexpected error: %v", err)
+	}
+	if len(empty.Annotations) != 0 {
+		t.Fatalf("Expected no annotations, got: %v", empty.Annotations)
+	}
+
+	saved := storage.GameData{Annotations: map[string]battleships.Annotation{
+		"A1": battleships.AnnotationSuspected,
+		"B2": battleships.AnnotationRuledOut,
+		"C3": "Q",
+	}}
+	if err = storage.SaveGame("key", saved); err != nil {
+		t.Fatalf("Received unexpected error: %v", err)
+	}
+
+	loaded, err := storage.LoadGame("key")
+	if err != nil {
+		t.Fatalf("Received unexpected error: %v", err)
+	}
+	for field, annotation := range saved.Annotations {
+		if loaded.Annotations[field] != annotation {
+			t.Fatalf("Incorrect annotation of %s; expected: %s, got: %s", field, annotation, loaded.Annotations[field])
+		}
+	}
+}
@@ -12,8 +12,8 @@ type GameThemes struct {
 }
 
 type Brush interface {
-	Char() byte
-	SetChar(char byte) Brush
+	Char() string
+	SetChar(char string) Brush
 	Style() lipgloss.Style
 	SetStyle(style lipgloss.Style) Brush
 }
@@ -41,6 +41,12 @@ type Theme interface {
 	SetMiss(brush Brush) Theme
 	Potential() Brush
 	SetPotential(brush Brush) Theme
+	Suspected() Brush
+	SetSuspected(brush Brush) Theme
+	RuledOut() Brush
+	SetRuledOut(brush Brush) Theme
+	Annotation() Brush
+	SetAnnotation(brush Brush) Theme
 
 	RenderBorder() string
 	RenderField(state FieldState) string
@@ -51,4 +57,18 @@ type Theme interface {
 	RenderSunk() string
 	RenderMiss() string
 	RenderPotential() string
+	RenderPreview() string
+
+	RenderAnnotation(annotation Annotation) string
+
+	RenderCursor(state FieldState) string
+	RenderAnnotationCursor(annotation Annotation) string
+	NewRenderCursor(state parts.State) string
+}
+
+// ThemesChangeMsg tells the models to draw with the new themes from now on.
+type ThemesChangeMsg struct {
+	// Name is the preset or the path of the theme file the themes come from
+	Name   string
+	Themes GameThemes
 }
@@ -0,0 +1,240 @@
+package board
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	battleships "github.com/kovansky/wp-battleships"
+	"github.com/kovansky/wp-battleships/parts"
+	"github.com/kovansky/wp-battleships/tui"
+	"time"
+)
+
+const (
+	animationInterval    = 90 * time.Millisecond
+	endAnimationInterval = 70 * time.Millisecond
+	// endFlashes is the number of times the result blinks, once it's written out
+	endFlashes = 6
+)
+
+// frame of a cell animation. Without a glyph, the field's own glyph is drawn. Only the glyphs and reversing
+// the colours are animated, so the animations work in terminals with a few colours too.
+type frame struct {
+	glyph   string
+	reverse bool
+}
+
+var animationFrames = map[battleships.FieldState][]frame{
+	// A splash spreading over the water
+	battleships.FieldStateMiss: {{".", false}, {"o", false}, {"O", false}, {"o", false}},
+	// An explosion, flashing the field
+	battleships.FieldStateHit: {{"*", true}, {"#", false}, {"*", true}, {"", false}, {"", true}, {"", false}, {"", true}},
+	// The whole ship flashes
+	battleships.FieldStateSunk: {{"#", true}, {"", false}, {"", true}, {"", false}, {"", true}, {"", false}, {"", true}, {"", false}, {"", true}},
+}
+
+// animation of the fields of one of the boards, after a shot.
+type animation struct {
+	// own is set for our board, which the opponent shoots at
+	own    bool
+	fields []string
+	result battleships.FieldState
+	frame  int
+}
+
+type animationMsg struct {
+	key string
+}
+
+type endAnimationMsg struct {
+	key string
+}
+
+func (c Full) animationsEnabled() bool {
+	return c.session.Settings.Game.Animations
+}
+
+// animateShot starts the animation of the shot at one of the boards. A sunk ship flashes as a whole.
+func (c Full) animateShot(own bool, field string, result battleships.FieldState) (Full, tea.Cmd) {
+	if !c.animationsEnabled() {
+		return c, nil
+	}
+
+	fields := []string{field}
+	if result == battleships.FieldStateSunk {
+		fields = c.sunkShip(own, field)
+	}
+
+	c.animations = append(c.animations, animation{own: own, fields: fields, result: result})
+	c = c.drawAnimations()
+
+	// The ticks are already running for the other animations
+	if len(c.animations) > 1 {
+		return c, nil
+	}
+
+	return c, c.animationTick()
+}
+
+// sunkShip returns the fields of the sunk ship with the field. Our ships are known, the opponent's are made up
+// of the fields we have hit around the one which has sunk it.
+func (c Full) sunkShip(own bool, field string) []string {
+	if own {
+		for _, ship := range c.Fleet() {
+			for _, f := range ship.Fields {
+				if f == field {
+					return ship.Fields
+				}
+			}
+		}
+
+		return []string{field}
+	}
+
+	var hit []string
+	for f, state := range c.OpponentBoard() {
+		if state == battleships.FieldStateHit || state == battleships.FieldStateSunk {
+			hit = append(hit, f)
+		}
+	}
+
+	ships, err := parts.SplitFleet(hit)
+	if err != nil {
+		return []string{field}
+	}
+
+	for _, ship := range ships {
+		if ship.Contains(field) {
+			return ship.Fields()
+		}
+	}
+
+	return []string{field}
+}
+
+func (c Full) animationTick() tea.Cmd {
+	key := c.Key()
+
+	return tea.Tick(animationInterval, func(time.Time) tea.Msg {
+		return animationMsg{key: key}
+	})
+}
+
+// updateAnimations moves the animations on to the next frame, and drops the finished ones.
+func (c Full) updateAnimations(msg animationMsg) (Full, tea.Cmd) {
+	if msg.key != c.Key() || len(c.animations) == 0 {
+		return c, nil
+	}
+
+	var running []animation
+	for _, a := range c.animations {
+		a.frame++
+		if a.frame < len(animationFrames[a.result]) {
+			running = append(running, a)
+		}
+	}
+	c.animations = running
+	c = c.drawAnimations()
+
+	if len(c.animations) == 0 {
+		return c, nil
+	}
+
+	return c, c.animationTick()
+}
+
+// drawAnimations puts the current frames of the animations over the boards.
+func (c Full) drawAnimations() Full {
+	friendly, opponent := make(map[string]string), make(map[string]string)
+
+	for _, a := range c.animations {
+		board, theme := opponent, c.themes.enemy
+		if a.own {
+			board, theme = friendly, c.themes.friendly
+		}
+
+		f := animationFrames[a.result][a.frame]
+		brush := fieldBrush(theme, a.result)
+
+		glyph := f.glyph
+		if glyph == "" {
+			glyph = brush.Char()
+		}
+
+		style := brush.Style().Copy().Reverse(f.reverse)
+		for _, field := range a.fields {
+			board[field] = tui.RenderCell(glyph, style)
+		}
+	}
+
+	c.friendly.SetOverlay(friendly)
+	c.opponent.SetOverlay(opponent)
+
+	return c
+}
+
+func fieldBrush(theme battleships.Theme, state battleships.FieldState) battleships.Brush {
+	switch state {
+	case battleships.FieldStateHit:
+		return theme.Hit()
+	case battleships.FieldStateSunk:
+		return theme.Sunk()
+	case battleships.FieldStateMiss:
+		return theme.Miss()
+	}
+
+	return theme.Ship()
+}
+
+// startEndAnimation writes the result out letter by letter, and then makes it blink.
+func (c Full) startEndAnimation() (Full, tea.Cmd) {
+	if !c.animationsEnabled() {
+		return c, nil
+	}
+
+	c.endAnimating, c.endFrame = true, 0
+
+	return c, c.endAnimationTick()
+}
+
+func (c Full) endAnimationTick() tea.Cmd {
+	key := c.Key()
+
+	return tea.Tick(endAnimationInterval, func(time.Time) tea.Msg {
+		return endAnimationMsg{key: key}
+	})
+}
+
+func (c Full) updateEndAnimation(msg endAnimationMsg) (Full, tea.Cmd) {
+	if msg.key != c.Key() || !c.endAnimating {
+		return c, nil
+	}
+
+	c.endFrame++
+	if c.endFrame >= len([]rune(c.endText()))+endFlashes {
+		c.endAnimating = false
+		return c, nil
+	}
+
+	return c, c.endAnimationTick()
+}
+
+func (c Full) endText() string {
+	if c.GameStatus().LastStatus == battleships.StatusWin {
+		return "You've won!"
+	}
+
+	return "You've lost :("
+}
+
+// endFrameText returns the part of the result written out so far, and whether it's blinking off right now.
+func (c Full) endFrameText() (string, bool) {
+	text := []rune(c.endText())
+	if !c.endAnimating {
+		return string(text), false
+	}
+
+	if c.endFrame < len(text) {
+		return string(text[:c.endFrame+1]), false
+	}
+
+	return string(text), (c.endFrame-len(text))%2 == 0
+}
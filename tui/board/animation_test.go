@@ -0,0 +1,92 @@
+package board
+
+import (
+	battleships "github.com/kovansky/wp-battleships"
+	"github.com/kovansky/wp-battleships/ships"
+	presets "github.com/kovansky/wp-battleships/tui/themes"
+	"github.com/rs/zerolog"
+	"testing"
+)
+
+func TestFull_Animations(t *testing.T) {
+	tests := []struct {
+		name    string
+		enabled bool
+		event   battleships.GameEvent
+		own     bool
+		fields  int
+	}{
+		{"our hit", true, battleships.ShotFired{Field: "B2", Result: battleships.FieldStateHit}, false, 1},
+		{"our miss", true, battleships.ShotFired{Field: "J10", Result: battleships.FieldStateMiss}, false, 1},
+		{"opponent sinks our ship", true, battleships.ShotReceived{Field: "A2", Result: battleships.FieldStateSunk}, true, 2},
+		{"disabled", false, battleships.ShotFired{Field: "B2", Result: battleships.FieldStateHit}, false, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			log := zerolog.Nop()
+			game := ships.NewGame("key", &log)
+			game.SetGameStatus(battleships.GameStatus{Status: battleships.StatusGameInProgress})
+			game.SetBoard(map[string]battleships.FieldState{"A1": battleships.FieldStateShip, "A2": battleships.FieldStateShip})
+
+			gameThemes, err := presets.Preset(presets.Default)
+			if err != nil {
+				t.Fatalf("Received unexpected error: %v", err)
+			}
+
+			session := battleships.NewSession(nil, gameThemes, battleships.DefaultSettings())
+			session.Settings.Game.Animations = test.enabled
+			session.SetGame(game)
+
+			c := InitFull(session, "")
+			game.RecordOpponentShots([]string{"A1", "A2"})
+
+			model, cmd := c.Update(test.event)
+			c = model.(Full)
+
+			board := &c.opponent
+			if test.own {
+				board = &c.friendly
+			}
+
+			if len(board.overlay) != test.fields {
+				t.Fatalf("Incorrect count of animated fields; expected: %d, got: %d", test.fields, len(board.overlay))
+			}
+			if test.fields == 0 {
+				return
+			}
+			if cmd == nil {
+				t.Fatalf("Expected the animation to be ticking")
+			}
+
+			// The animation ends on its own, leaving the board as it is
+			for i := 0; i < 20 && len(c.animations) > 0; i++ {
+				c, _ = c.updateAnimations(animationMsg{key: c.Key()})
+			}
+			if len(c.animations) > 0 || len(board.overlay) > 0 {
+				t.Fatalf("Expected the animation to have ended")
+			}
+		})
+	}
+}
+
+func TestFull_EndAnimation(t *testing.T) {
+	c := createEndedGame(t, battleships.PlayModeWait)
+
+	c, cmd := c.startEndAnimation()
+	if cmd == nil {
+		t.Fatalf("Expected the result to be animated")
+	}
+
+	if text, _ := c.endFrameText(); text != "Y" {
+		t.Fatalf("Incorrect first frame; expected: Y, got: %q", text)
+	}
+
+	for cmd != nil {
+		c, cmd = c.updateEndAnimation(endAnimationMsg{key: c.Key()})
+	}
+
+	if text, flash := c.endFrameText(); text != "You've won!" || flash {
+		t.Fatalf("Incorrect last frame; got: %q, flashing: %t", text, flash)
+	}
+}
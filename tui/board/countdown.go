@@ -0,0 +1,110 @@
+package board
+
+import (
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	battleships "github.com/kovansky/wp-battleships"
+	"github.com/kovansky/wp-battleships/tui"
+	"math"
+	"strconv"
+	"time"
+)
+
+const (
+	countdownInterval = 200 * time.Millisecond
+	// autoFireMargin leaves time for the shot to reach the server before the turn times out
+	autoFireMargin = 1500 * time.Millisecond
+)
+
+type countdownMsg time.Time
+
+func countdownTick() tea.Cmd {
+	return tea.Tick(countdownInterval, func(t time.Time) tea.Msg {
+		return countdownMsg(t)
+	})
+}
+
+// syncCountdown resets the local deadline using the timer received from the server.
+func (c Full) syncCountdown() Full {
+	status := c.GameStatus()
+
+	if status.Status != battleships.StatusGameInProgress || !status.ShouldFire {
+		c.deadline = time.Time{}
+		c.warned = false
+		c.autoFired = false
+		return c
+	}
+
+	c.deadline = time.Now().Add(time.Duration(status.Timer) * time.Second)
+
+	// The server resets the timer after each shot, so the safeguards have to be armed again
+	if c.remaining() > time.Duration(c.session.Settings.Timer.WarningThreshold)*time.Second {
+		c.warned = false
+		c.autoFired = false
+	}
+
+	return c
+}
+
+// remaining returns the time left in our turn.
+func (c Full) remaining() time.Duration {
+	if c.deadline.IsZero() {
+		return 0
+	}
+
+	left := time.Until(c.deadline)
+	if left < 0 {
+		return 0
+	}
+
+	return left
+}
+
+func (c Full) updateCountdown() (Full, tea.Cmd) {
+	c.ringing = false
+
+	if c.GameStatus().Status == battleships.StatusEnded {
+		return c, nil
+	}
+
+	cmds := []tea.Cmd{countdownTick()}
+
+	if c.deadline.IsZero() {
+		return c, tea.Batch(cmds...)
+	}
+
+	settings := c.session.Settings.Timer
+	left := c.remaining()
+
+	if !c.warned && left <= time.Duration(settings.WarningThreshold)*time.Second {
+		c.warned = true
+
+		c.ringing = settings.Bell
+	}
+
+	if settings.AutoFire && !c.autoFired && !c.firingQueue && left <= autoFireMargin {
+		if field, ok := c.bestGuess(); ok {
+			var cmd tea.Cmd
+
+			c.autoFired = true
+			c, cmd = c.fire(field)
+			cmds = append(cmds, cmd)
+
+			if _, shot := c.OpponentBoard()[field]; shot {
+				cmds = append(cmds, tui.Notify(tui.SeverityWarning, fmt.Sprintf("Time was running out - fired at %s automatically", field)))
+			}
+		}
+	}
+
+	return c, tea.Batch(cmds...)
+}
+
+func (c Full) countdownView() string {
+	seconds := strconv.Itoa(int(math.Ceil(c.remaining().Seconds())))
+
+	if c.warned {
+		return c.themes.global.TextSecondary().Copy().Bold(true).Blink(true).Render(seconds) + " seconds left to fire - hurry up!"
+	}
+
+	return c.themes.global.TextSecondary().Render(seconds) + " seconds left to fire"
+}
@@ -0,0 +1,41 @@
+package board
+
+import (
+	battleships "github.com/kovansky/wp-battleships"
+	"github.com/kovansky/wp-battleships/ships"
+	"github.com/kovansky/wp-battleships/tui"
+	"github.com/rs/zerolog"
+	"testing"
+	"time"
+)
+
+func TestFull_CountdownAfterHit(t *testing.T) {
+	log := zerolog.Nop()
+
+	client := &stubClient{
+		results: map[string]battleships.ShotState{"A1": battleships.ShotHit},
+		status:  &battleships.GameStatus{Status: battleships.StatusGameInProgress, ShouldFire: true, Timer: 60},
+	}
+	game := ships.NewGame("", &log)
+	game.SetGameStatus(battleships.GameStatus{Status: battleships.StatusGameInProgress, ShouldFire: true, Timer: 5})
+
+	theme := tui.NewTheme()
+	session := battleships.NewSession(client, battleships.GameThemes{Player: theme, Enemy: theme, Global: theme}, battleships.DefaultSettings())
+	session.SetGame(game)
+
+	c := InitFull(session, "")
+	c, _ = c.updateCountdown()
+	if !c.warned {
+		t.Fatalf("Expected the warning with 5 seconds left")
+	}
+
+	// The turn stays ours after the hit, and the server resets the timer
+	c, _ = c.fire("A1")
+
+	if left := c.remaining(); left < 50*time.Second {
+		t.Fatalf("Deadline wasn't reset after the hit; %v left", left)
+	}
+	if c.warned || c.autoFired {
+		t.Fatalf("Safeguards weren't armed again after the hit")
+	}
+}
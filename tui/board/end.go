@@ -0,0 +1,105 @@
+package board
+
+import (
+	"fmt"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	battleships "github.com/kovansky/wp-battleships"
+	"github.com/kovansky/wp-battleships/tui"
+	"time"
+)
+
+// autoStartDelay lets the player look at the result, or choose something else, before the next game starts.
+const autoStartDelay = 5 * time.Second
+
+type autoStartMsg struct {
+	key string
+}
+
+// endGame arms the automatic start of the next game, if the player wants it.
+func (c Full) endGame() (Full, tea.Cmd) {
+	c = c.syncCountdown()
+
+	if !c.session.Settings.Game.AutoStart {
+		return c, nil
+	}
+
+	c.autoStart = true
+	key := c.Key()
+
+	return c, tea.Tick(autoStartDelay, func(time.Time) tea.Msg {
+		return autoStartMsg{key: key}
+	})
+}
+
+// updateEnd handles the choice on the end-of-game screen. Any key cancels the automatic start.
+func (c Full) updateEnd(msg tea.KeyMsg) (Full, tea.Cmd) {
+	c.autoStart = false
+
+	keys := c.session.Keys.End
+
+	switch {
+	case key.Matches(msg, keys.Rematch):
+		if c.opponentName() == "" {
+			return c, tui.Notify(tui.SeverityWarning, "The opponent is not known, there is no one to challenge")
+		}
+
+		return c, c.next(tui.NextGameRematch)
+	case key.Matches(msg, keys.PlayAgain):
+		return c, c.next(tui.NextGamePlayAgain)
+	case key.Matches(msg, keys.Lobby):
+		return c, c.next(tui.NextGameLobby)
+	case key.Matches(msg, keys.Setup):
+		return c, c.next(tui.NextGameSetup)
+	}
+
+	return c, nil
+}
+
+// autoStartNext starts the next game the same way the last one was started: a rematch if we have challenged
+// the opponent, otherwise waiting for a new one.
+func (c Full) autoStartNext(msg autoStartMsg) (Full, tea.Cmd) {
+	if !c.autoStart || msg.key != c.Key() {
+		return c, nil
+	}
+	c.autoStart = false
+
+	if c.session.Player().PlayMode == battleships.PlayModeChallenge && c.opponentName() != "" {
+		return c, c.next(tui.NextGameRematch)
+	}
+
+	return c, c.next(tui.NextGamePlayAgain)
+}
+
+func (c Full) next(choice tui.NextGame) tea.Cmd {
+	opponent := c.opponentName()
+
+	return func() tea.Msg {
+		return tui.NextGameMsg{Choice: choice, Opponent: opponent}
+	}
+}
+
+func (c Full) opponentName() string {
+	if c.Opponent() == nil {
+		return ""
+	}
+
+	return c.Opponent().Name()
+}
+
+func (c Full) endView() string {
+	keys := c.session.Keys.End
+
+	options := tui.KeyOptions(keys.Rematch, keys.PlayAgain, keys.Lobby, keys.Setup)
+	if c.opponentName() == "" {
+		options = tui.KeyOptions(keys.PlayAgain, keys.Lobby, keys.Setup)
+	}
+
+	view := "\n" + options
+
+	if c.autoStart {
+		view += fmt.Sprintf("\nThe next game starts in %d seconds, press any key to stay.", int(autoStartDelay.Seconds()))
+	}
+
+	return view
+}
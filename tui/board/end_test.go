@@ -0,0 +1,73 @@
+package board
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	battleships "github.com/kovansky/wp-battleships"
+	"github.com/kovansky/wp-battleships/ships"
+	"github.com/kovansky/wp-battleships/tui"
+	"github.com/rs/zerolog"
+	"testing"
+)
+
+func createEndedGame(t *testing.T, mode battleships.PlayMode) Full {
+	t.Helper()
+
+	log := zerolog.Nop()
+	game := ships.NewGame("key", &log)
+	game.SetOpponent(ships.NewPlayer("opponent", ""))
+	game.SetGameStatus(battleships.GameStatus{Status: battleships.StatusEnded, LastStatus: battleships.StatusWin})
+
+	theme := tui.NewTheme()
+	session := battleships.NewSession(nil, battleships.GameThemes{Player: theme, Enemy: theme, Global: theme}, battleships.DefaultSettings())
+	session.Settings.Game.AutoStart = true
+	session.SetPlayer(battleships.PlayerData{PlayMode: mode})
+	session.SetGame(game)
+
+	c, cmd := InitFull(session, "").endGame()
+	if cmd == nil || !c.autoStart {
+		t.Fatalf("Expected the next game to be started automatically")
+	}
+
+	return c
+}
+
+func TestFull_AutoStart(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     battleships.PlayMode
+		expected tui.NextGame
+	}{
+		{"challenge", battleships.PlayModeChallenge, tui.NextGameRematch},
+		{"wait", battleships.PlayModeWait, tui.NextGamePlayAgain},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c, cmd := createEndedGame(t, test.mode).autoStartNext(autoStartMsg{key: "key"})
+			if cmd == nil {
+				t.Fatalf("Expected the next game to start")
+			}
+
+			msg, ok := cmd().(tui.NextGameMsg)
+			if !ok || msg.Choice != test.expected || msg.Opponent != "opponent" {
+				t.Fatalf("Incorrect next game; expected: %v, got: %+v", test.expected, msg)
+			}
+			if c.autoStart {
+				t.Fatalf("Automatic start should happen only once")
+			}
+		})
+	}
+}
+
+func TestFull_AutoStartCancelled(t *testing.T) {
+	c := createEndedGame(t, battleships.PlayModeChallenge)
+
+	c, cmd := c.updateEnd(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	if cmd != nil {
+		t.Fatalf("Unknown key should not choose the next game")
+	}
+
+	if _, cmd = c.autoStartNext(autoStartMsg{key: "key"}); cmd != nil {
+		t.Fatalf("Automatic start should be cancelled by a key press")
+	}
+}
@@ -0,0 +1,104 @@
+package board
+
+import (
+	"fmt"
+	"github.com/charmbracelet/lipgloss"
+	battleships "github.com/kovansky/wp-battleships"
+	"github.com/kovansky/wp-battleships/parts"
+	"github.com/kovansky/wp-battleships/tui"
+	"sort"
+	"strings"
+)
+
+// shipsWidth fits the glyphs of the biggest ship.
+const shipsWidth = 4 * tui.CellWidth
+
+// fleetLayout splits our board into ships, the biggest first. The board the player has set up is used,
+// or the one the server has drawn, if there is none.
+func fleetLayout(player battleships.PlayerData, board map[string]battleships.FieldState) [][]string {
+	fields := player.Board
+	if len(fields) == 0 {
+		for field, state := range board {
+			if state != battleships.FieldStateMiss {
+				fields = append(fields, field)
+			}
+		}
+	}
+
+	ships, err := parts.SplitFleet(fields)
+	if err != nil {
+		return nil
+	}
+
+	fleet := make([][]string, len(ships))
+	for i, ship := range ships {
+		fleet[i] = ship.Fields()
+	}
+	sort.SliceStable(fleet, func(i, j int) bool {
+		return len(fleet[i]) > len(fleet[j])
+	})
+
+	return fleet
+}
+
+// fleetRemaining returns the number of our ships still afloat, and of all of them.
+func (c Full) fleetRemaining() (int, int) {
+	fleet := c.Fleet()
+
+	remaining := len(fleet)
+	for _, ship := range fleet {
+		if ship.Sunk() {
+			remaining--
+		}
+	}
+
+	return remaining, len(fleet)
+}
+
+// fleetView lists our ships with their damage.
+func (c Full) fleetView() string {
+	fleet := c.Fleet()
+	if len(fleet) == 0 {
+		return ""
+	}
+
+	lines := []string{c.themes.global.TextPrimary().Copy().Bold(true).Render("Your fleet")}
+
+	for _, ship := range fleet {
+		position := ship.Fields[0]
+		if len(ship.Fields) > 1 {
+			position += "-" + ship.Fields[len(ship.Fields)-1]
+		}
+
+		status := "afloat"
+		switch {
+		case ship.Sunk():
+			status = "sunk"
+		case ship.Hits > 0:
+			status = "damaged"
+		}
+
+		// One glyph per field of the ship, the hit ones first
+		glyphs := strings.Repeat(c.themes.friendly.RenderHit(), ship.Hits) + strings.Repeat(c.themes.friendly.RenderShip(), len(ship.Fields)-ship.Hits)
+		if ship.Sunk() {
+			glyphs = strings.Repeat(c.themes.friendly.RenderSunk(), len(ship.Fields))
+		}
+
+		glyphs = lipgloss.NewStyle().Width(shipsWidth).Render(glyphs)
+
+		lines = append(lines, fmt.Sprintf("%-7s %s %d/%d hit  %s", position, glyphs, ship.Hits, len(ship.Fields), status))
+	}
+
+	lines = append(lines, c.fleetSummary())
+
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+func (c Full) fleetSummary() string {
+	remaining, all := c.fleetRemaining()
+	if all == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("Ships remaining: %d/%d", remaining, all)
+}
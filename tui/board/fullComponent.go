@@ -3,15 +3,18 @@ package board
 import (
 	"fmt"
 	"github.com/76creates/stickers"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	battleships "github.com/kovansky/wp-battleships"
+	"github.com/kovansky/wp-battleships/storage"
 	"github.com/kovansky/wp-battleships/tui"
 	"github.com/mbndr/figlet4go"
 	"math"
-	"strconv"
 	"strings"
 	"time"
+	"unicode"
 )
 
 type themes struct {
@@ -21,24 +24,70 @@ type themes struct {
 }
 
 type Full struct {
-	themes themes
+	session *battleships.Session
+	themes  themes
 
-	friendly     Single
-	opponent     Single
-	playersInfo  string
-	displayError string
+	friendly    Single
+	opponent    Single
+	playersInfo string
 
 	flexbox     *stickers.FlexBox
 	asciiRender *figlet4go.AsciiRender
 
 	targetInput textinput.Model
 
+	// awaitingLetter is set after the custom mark key, the next letter becomes the mark
+	awaitingLetter bool
+
+	// queue holds shots prepared during the opponent's turn
+	queue       []string
+	firingQueue bool
+
+	// deadline of our turn, counted down locally between status updates
+	deadline  time.Time
+	warned    bool
+	autoFired bool
+	// ringing puts the bell into the next frame
+	ringing bool
+
+	// autoStart is set while waiting to start the next game on its own
+	autoStart bool
+
+	// confirmLeave is set while asking the player whether to abandon the game
+	confirmLeave bool
+
+	width, height int
+	// ownBoard shows our board instead of the opponent's, when only one of them fits
+	ownBoard bool
+
+	// historyScroll is the number of the latest shots scrolled past in the history panel
+	historyScroll int
+
+	// animations of the last shots, drawn over the boards
+	animations []animation
+	// endFrame is the frame of the result animation, while endAnimating is set
+	endAnimating bool
+	endFrame     int
+
 	battleships.Game
 }
 
-func InitFull(game battleships.Game, themeFriendly, themeEnemy, themeGlobal battleships.Theme, playersInfo string) Full {
-	friendly := InitSingle(themeFriendly, game.Board())
-	opponent := InitSingle(themeEnemy, game.OpponentBoard())
+func InitFull(session *battleships.Session, playersInfo string) Full {
+	game := session.Game()
+
+	friendly := InitSingle(session.Themes().Player, session.Keys.Board, game.Board())
+	opponent := InitSingle(session.Themes().Enemy, session.Keys.Board, game.OpponentBoard())
+
+	if game.Annotations() == nil {
+		// Marks are kept locally, restore them if we come back to the same game
+		if data, err := storage.LoadGame(game.Key()); err == nil && data.Annotations != nil {
+			game.SetAnnotations(data.Annotations)
+		}
+	}
+	opponent.SetAnnotations(game.Annotations())
+	friendly.SetHighlight(lastOpponentShot(game.History()))
+	game.SetFleet(fleetLayout(session.Player(), game.Board()))
+	friendly.SetBoard(game.Board())
 	flexbox := stickers.NewFlexBox(0, 0)
 	asciiRender := figlet4go.NewAsciiRender()
 
@@ -47,8 +96,11 @@ func InitFull(game battleships.Game, themeFriendly, themeEnemy, themeGlobal batt
 	targetInput.CharLimit = 3
 	targetInput.Width = 25
 
-	return Full{
-		themes:      themes{themeFriendly, themeEnemy, themeGlobal},
+	opponent.Focus()
+
+	full := Full{
+		session:     session,
+		themes:      themes{session.Themes().Player, session.Themes().Enemy, session.Themes().Global},
 		friendly:    friendly,
 		opponent:    opponent,
 		playersInfo: playersInfo,
@@ -57,6 +109,9 @@ func InitFull(game battleships.Game, themeFriendly, themeEnemy, themeGlobal batt
 		targetInput: targetInput,
 		Game:        game,
 	}
+
+	// The turn may have begun before we subscribed to the game events
+	return full.syncCountdown()
 }
 
 func (c Full) Init() tea.Cmd {
@@ -87,7 +142,7 @@ func (c Full) Init() tea.Cmd {
 	}
 	c.flexbox.AddRows(rows)
 
-	return textinput.Blink
+	return tea.Batch(textinput.Blink, countdownTick())
 }
 
 func (c Full) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -98,76 +153,172 @@ func (c Full) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c":
-			return c, tea.Quit
-		case "enter":
-			field := strings.ToUpper(c.targetInput.Value())
-			if !fieldWithinBoard(field) {
-				c.displayError = "Field outside of board"
-				break
-			}
+		if c.GameStatus().Status == battleships.StatusEnded {
+			c, cmd = c.updateEnd(msg)
+			return c, cmd
+		}
+
+		if c.confirmLeave {
+			c, cmd = c.updateLeave(msg)
+			return c, cmd
+		}
 
-			if _, exists := c.OpponentBoard()[field]; exists {
-				c.displayError = "You already fired at this field!"
-				break
+		if c.awaitingLetter {
+			c.awaitingLetter = false
+
+			// Marks are ASCII letters only, they are rendered as a single-byte glyph
+			if len(msg.Runes) == 1 && msg.Runes[0] <= unicode.MaxASCII && unicode.IsLetter(msg.Runes[0]) {
+				c, cmd = c.annotate(battleships.Annotation(unicode.ToUpper(msg.Runes[0])))
 			}
 
-			c.displayError = ""
-			c.targetInput.SetValue("")
+			return c, cmd
+		}
 
-			c.Statistics().IncrementShots()
-			shotState, err := battleships.ServerClient.Fire(c.Game, field)
-			if err != nil {
-				c.displayError = "Error firing: " + err.Error()
-				break
+		keys := c.session.Keys.Game
+
+		if c.opponent.Focused() {
+			switch {
+			case key.Matches(msg, keys.Suspected):
+				c, cmd = c.annotate(battleships.AnnotationSuspected)
+				return c, cmd
+			case key.Matches(msg, keys.RuledOut):
+				c, cmd = c.annotate(battleships.AnnotationRuledOut)
+				return c, cmd
+			case key.Matches(msg, keys.Mark):
+				c.awaitingLetter = true
+				return c, nil
+			case key.Matches(msg, keys.Unmark):
+				c, cmd = c.annotate("")
+				return c, cmd
+			case key.Matches(msg, keys.Queue):
+				c, cmd = c.toggleQueued(c.opponent.Cursor())
+				return c, cmd
+			case key.Matches(msg, keys.SwitchBoard) && c.layout() == layoutSingle:
+				c.ownBoard = !c.ownBoard
+				return c, nil
 			}
-			var fieldState battleships.FieldState
-			switch shotState {
-			case battleships.ShotMiss:
-				fieldState = battleships.FieldStateMiss
-			case battleships.ShotHit:
-				fieldState = battleships.FieldStateHit
-				c.Statistics().IncrementHits()
-			case battleships.ShotSunk:
-				fieldState = battleships.FieldStateSunk
-				c.Statistics().IncrementHits()
-				c.Statistics().IncrementSunk()
+		}
+
+		switch {
+		case key.Matches(msg, keys.HistoryUp):
+			return c.scrollHistory(1), nil
+		case key.Matches(msg, keys.HistoryDown):
+			return c.scrollHistory(-1), nil
+		case key.Matches(msg, keys.Leave):
+			c.confirmLeave = true
+			return c, nil
+		case key.Matches(msg, keys.ClearQueue):
+			c.queue = nil
+			return c, nil
+		case key.Matches(msg, keys.Focus):
+			if c.opponent.Focused() {
+				c.opponent.Blur()
+				cmds = append(cmds, c.targetInput.Focus())
+			} else {
+				c.opponent.Focus()
+				c.targetInput.Blur()
 			}
 
-			board := c.OpponentBoard()
-			if board == nil {
-				board = make(map[string]battleships.FieldState)
+			return c, tea.Batch(cmds...)
+		case key.Matches(msg, keys.Fire):
+			field := c.opponent.Cursor()
+			if !c.opponent.Focused() {
+				field = strings.ToUpper(c.targetInput.Value())
+				if !fieldWithinBoard(field) {
+					cmds = append(cmds, tui.Notify(tui.SeverityWarning, "Field outside of board"))
+					break
+				}
 			}
 
-			board[field] = fieldState
+			// Outside of our turn, shots are queued for later
+			if !c.GameStatus().ShouldFire && c.GameStatus().Status != battleships.StatusEnded {
+				c, cmd = c.toggleQueued(field)
+				c.targetInput.SetValue("")
+				return c, cmd
+			}
 
-			c.SetOpponentBoard(board)
-			c.opponent.SetBoard(c.OpponentBoard())
+			c, cmd = c.fire(field)
+			return c, cmd
+		}
+	case tea.MouseMsg:
+		// The boards can't be clicked behind the question or the end of the game
+		if c.confirmLeave || c.GameStatus().Status == battleships.StatusEnded {
+			return c, nil
+		}
 
-			c.targetInput.Blur()
+		if msg.Type != tea.MouseLeft {
+			break
 		}
+
+		x, y, visible := c.opponentOrigin()
+		if !visible {
+			break
+		}
+
+		field, ok := c.opponent.FieldAt(msg.X-x, msg.Y-y)
+		if !ok {
+			break
+		}
+
+		c.opponent.SetCursor(field)
+		c, cmd = c.fire(field)
+		cmds = append(cmds, cmd)
 	case tea.WindowSizeMsg:
+		c.width, c.height = msg.Width, msg.Height
 		c.flexbox.SetWidth(msg.Width)
 		c.flexbox.SetHeight(msg.Height)
-	case battleships.GameUpdateMsg:
-		switch c.GameStatus().Status {
-		case battleships.StatusGameInProgress:
-			if c.GameStatus().ShouldFire {
-				cmds = append(cmds, c.targetInput.Focus())
-			}
-		case battleships.StatusEnded:
-			go func() {
-				time.Sleep(5 * time.Second)
-
-				battleships.ProgramMessage(tui.ApplicationStageChangeMsg{
-					From:  tui.StageGame,
-					Stage: tui.StageLogin,
-				})
-			}()
+	case battleships.StatusChanged:
+		c = c.syncCountdown()
+	case battleships.TurnChanged:
+		c = c.syncCountdown()
+
+		if !c.opponent.Focused() && !c.targetInput.Focused() {
+			cmds = append(cmds, c.targetInput.Focus())
 		}
+
+		c, cmd = c.startQueue()
+		cmds = append(cmds, cmd)
+	case battleships.ShotFired:
+		c.opponent.SetBoard(c.OpponentBoard())
+		c = c.keepHistoryScrolled()
+
+		c, cmd = c.animateShot(false, msg.Field, msg.Result)
+		cmds = append(cmds, cmd)
+	case battleships.ShotReceived:
+		c.friendly.SetBoard(c.Board())
+		c.friendly.SetHighlight(msg.Field)
+		c = c.keepHistoryScrolled()
+
+		c, cmd = c.animateShot(true, msg.Field, msg.Result)
+		cmds = append(cmds, cmd)
+	case battleships.GameEnded:
+		c, cmd = c.endGame()
+		cmds = append(cmds, cmd)
+
+		c, cmd = c.startEndAnimation()
+		cmds = append(cmds, cmd)
+	case animationMsg:
+		c, cmd = c.updateAnimations(msg)
+		return c, cmd
+	case endAnimationMsg:
+		c, cmd = c.updateEndAnimation(msg)
+		return c, cmd
+	case autoStartMsg:
+		c, cmd = c.autoStartNext(msg)
+		return c, cmd
+	case battleships.ThemesChangeMsg:
+		c.themes = themes{msg.Themes.Player, msg.Themes.Enemy, msg.Themes.Global}
+		c.friendly.SetTheme(msg.Themes.Player)
+		c.opponent.SetTheme(msg.Themes.Enemy)
+		c = c.drawAnimations()
 	case battleships.PlayersUpdateMsg:
 		c.playersInfo = msg.PlayersInfo
+	case queuedShotMsg:
+		c, cmd = c.fireNextQueued()
+		return c, cmd
+	case countdownMsg:
+		c, cmd = c.updateCountdown()
+		return c, cmd
 	}
 
 	c.friendly, cmd = c.friendly.Update(msg)
@@ -181,13 +332,62 @@ func (c Full) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (c Full) View() string {
-	friendlyRender, _ := c.asciiRender.Render("Friendly")
-	enemyRender, _ := c.asciiRender.Render("Enemy")
-	gameInfoRender, _ := c.asciiRender.Render("Game Info")
+	// The bell goes out with the frame, so it isn't mixed up with the renderer's output
+	var bell string
+	if c.ringing {
+		bell = "\a"
+	}
+
+	if c.layout() != layoutWide {
+		return c.compactView() + bell
+	}
+
+	friendlyState, enemyState := c.titleStyles()
+
+	gameInfo := c.gameInfo() + "\n\n" + c.historyView() + fmt.Sprintf("\n\nLegend:\n\t%s - ship\n\t%s - hit\n\t%s - sunk\n\t%s - miss\n\t%s - suspected ship\n\t%s - ruled out",
+		c.themes.friendly.RenderShip(),
+		c.themes.friendly.RenderHit(),
+		c.themes.enemy.RenderSunk(),
+		c.themes.enemy.RenderMiss(),
+		c.themes.enemy.RenderAnnotation(battleships.AnnotationSuspected),
+		c.themes.enemy.RenderAnnotation(battleships.AnnotationRuledOut),
+	)
+	gameInfo += "\nYou win when you sink all opponent's ships (one 4-square, two 3sq, three 2sq and four 1sq).\n" + c.keysInfo()
+
+	c.flexbox.Row(0).Cell(0).SetContent(c.titleView("Friendly", friendlyState))
+	c.flexbox.Row(0).Cell(1).SetContent(c.titleView("Enemy", enemyState))
+	c.flexbox.Row(0).Cell(2).SetContent(c.titleView("Game Info", c.themes.global.TextPrimary()))
+
+	c.flexbox.Row(1).Cell(0).SetContent(c.friendly.View() + "\n\n" + c.fleetView())
+	c.flexbox.Row(1).Cell(1).SetContent(c.opponent.View())
+	c.flexbox.Row(1).Cell(2).SetContent(gameInfo)
+
+	c.flexbox.Row(2).Cell(0).SetContent(c.statusView())
+
+	return c.flexbox.Render() + bell
+}
+
+// titleStyles return the styles of the boards' titles. The board of the player whose turn it is stands out.
+func (c Full) titleStyles() (friendly, enemy lipgloss.Style) {
+	if c.GameStatus().ShouldFire {
+		return c.themes.global.TextSecondary(), c.themes.global.TextPrimary()
+	}
+
+	return c.themes.global.TextPrimary(), c.themes.global.TextSecondary()
+}
 
-	friendlyState := c.themes.global.TextPrimary()
-	enemyState := c.themes.global.TextPrimary()
+// titleView renders the title of a column as ASCII art, or as it is if the art is wider than the column.
+func (c Full) titleView(title string, style lipgloss.Style) string {
+	render, _ := c.asciiRender.Render(title)
+	if lipgloss.Width(render) > c.width/3 {
+		return style.Copy().Bold(true).Render(title)
+	}
+
+	return style.Render(render)
+}
 
+// gameInfo describes the players and the state of the game.
+func (c Full) gameInfo() string {
 	gameInfo := c.playersInfo
 
 	percentage := float64(c.Statistics().Hits()) / float64(c.Statistics().Shots()) * 100
@@ -197,47 +397,140 @@ func (c Full) View() string {
 	gameInfo += fmt.Sprintf("\n\n%d hits out of %d shots (including %d (of 10) sunk) - %.2f%%", c.Statistics().Hits(), c.Statistics().Shots(), c.Statistics().Sunk(), percentage)
 
 	if c.GameStatus().ShouldFire {
-		friendlyState = c.themes.global.TextSecondary()
+		gameInfo += "\n\nYour turn!\n\t" + c.countdownView()
+	}
 
-		gameInfo += "\n\nYour turn!\n\t" + c.themes.global.TextSecondary().Render(strconv.Itoa(c.GameStatus().Timer)) + " seconds left to fire"
-	} else {
-		enemyState = c.themes.global.TextSecondary()
+	return gameInfo + c.queueInfo()
+}
+
+// statusView shows the prompt during the game, and the result once it has ended.
+func (c Full) statusView() string {
+	// The compact layout has no room for the spacing and the ASCII art
+	compact := c.layout() != layoutWide
+	spacing := "\n\n\n"
+	if compact {
+		spacing = "\n"
 	}
 
-	gameInfo += fmt.Sprintf("\n\nLegend:\n\t%s - ship\n\t%s - hit\n\t%s - sunk\n\t%s - miss",
-		c.themes.friendly.RenderShip(),
-		c.themes.friendly.RenderHit(),
-		c.themes.enemy.RenderSunk(),
-		c.themes.enemy.RenderMiss(),
-	)
-	gameInfo += fmt.Sprintf("\nYou win when you sink all opponent's ships (one 4-square, two 3sq, three 2sq and four 1sq).\n" +
-		"To fire in your turn, type in the coordinate (i.e. A1) in the field below the boards. If you hit, you can fire again.")
+	if c.GameStatus().Status == battleships.StatusGameInProgress {
+		prompt := c.targetInput.View()
+		if c.opponent.Focused() {
+			action := "fire"
+			if !c.GameStatus().ShouldFire {
+				action = "queue the shot"
+			}
 
-	c.flexbox.Row(0).Cell(0).SetContent(friendlyState.Render(friendlyRender))
-	c.flexbox.Row(0).Cell(1).SetContent(enemyState.Render(enemyRender))
-	c.flexbox.Row(0).Cell(2).SetContent(c.themes.global.TextPrimary().Render(gameInfoRender))
+			prompt = fmt.Sprintf("Aiming at %s - press %s to %s", c.themes.global.TextPrimary().Render(c.opponent.Cursor()), c.session.Keys.Game.Fire.Help().Key, action)
+		}
 
-	c.flexbox.Row(1).Cell(0).SetContent(c.friendly.View())
-	c.flexbox.Row(1).Cell(1).SetContent(c.opponent.View())
-	c.flexbox.Row(1).Cell(2).SetContent(gameInfo)
+		if c.confirmLeave {
+			prompt = c.leaveView()
+		}
 
-	if c.GameStatus().Status == battleships.StatusGameInProgress && c.GameStatus().ShouldFire {
-		c.flexbox.Row(2).Cell(0).SetContent("\n\n\n" + c.targetInput.View() + "\n" + c.themes.global.TextSecondary().Render(c.displayError))
+		return spacing + prompt
 	} else if c.GameStatus().Status == battleships.StatusEnded {
-		victory := c.GameStatus().LastStatus == battleships.StatusWin
-		endString, _ := c.asciiRender.Render("You've won!")
+		endString, flash := c.endFrameText()
 		endColor := c.themes.global.TextPrimary()
-		if !victory {
-			endString, _ = c.asciiRender.Render("You've lost :(")
+		if c.GameStatus().LastStatus != battleships.StatusWin {
 			endColor = c.themes.global.TextSecondary()
 		}
+		if flash {
+			endColor = endColor.Copy().Reverse(true)
+		}
+
+		if compact {
+			endString = "\n" + endColor.Copy().Bold(true).Render(endString)
+		} else {
+			endString, _ = c.asciiRender.Render(endString)
+			endString = endColor.Render(endString)
+		}
 
-		c.flexbox.Row(2).Cell(0).SetContent(endColor.Render(endString))
+		return endString + c.endView()
+	} else if c.confirmLeave {
+		return spacing + c.leaveView()
+	}
+
+	return ""
+}
+
+// fire shoots at given field of the opponent's board.
+func (c Full) fire(field string) (Full, tea.Cmd) {
+	if c.GameStatus().Status != battleships.StatusGameInProgress || !c.GameStatus().ShouldFire {
+		return c, tui.Notify(tui.SeverityInfo, "Wait for your turn!")
+	}
+
+	if _, exists := c.OpponentBoard()[field]; exists {
+		return c, tui.Notify(tui.SeverityWarning, "You already fired at this field!")
+	}
+
+	c.targetInput.SetValue("")
+
+	// Shooting by hand takes the field out of the queue
+	c.queue = removeField(c.queue, field)
+
+	shotState, err := c.session.Client.Fire(c.Game, field)
+	if err != nil {
+		return c, tui.Notify(tui.SeverityError, "Error firing: "+err.Error())
+	}
+	var fieldState battleships.FieldState
+	switch shotState {
+	case battleships.ShotMiss:
+		fieldState = battleships.FieldStateMiss
+	case battleships.ShotHit:
+		fieldState = battleships.FieldStateHit
+	case battleships.ShotSunk:
+		fieldState = battleships.FieldStateSunk
+	}
+
+	c.RecordShot(field, fieldState)
+	// The server resets the timer after each shot, Fire has fetched the new one already
+	c = c.syncCountdown()
+	c.opponent.SetBoard(c.OpponentBoard())
+	c = c.pruneQueue()
+
+	// The field is resolved, the mark is not needed anymore
+	if annotations := c.Annotations(); annotations[field] != "" {
+		delete(annotations, field)
+		c.SetAnnotations(annotations)
+		c.opponent.SetAnnotations(annotations)
+		return c.saveAnnotations()
+	}
+
+	return c, nil
+}
+
+// annotate toggles the mark on the field under the cursor. Empty annotation removes the mark.
+func (c Full) annotate(annotation battleships.Annotation) (Full, tea.Cmd) {
+	field := c.opponent.Cursor()
+
+	if _, shot := c.OpponentBoard()[field]; shot {
+		return c, tui.Notify(tui.SeverityWarning, "You can only mark fields you haven't fired at")
+	}
+
+	annotations := c.Annotations()
+	if annotations == nil {
+		annotations = make(map[string]battleships.Annotation)
+	}
+
+	if annotation == "" || annotations[field] == annotation {
+		delete(annotations, field)
 	} else {
-		c.flexbox.Row(2).Cell(0).SetContent("")
+		annotations[field] = annotation
 	}
 
-	return c.flexbox.Render()
+	c.SetAnnotations(annotations)
+	c.opponent.SetAnnotations(annotations)
+
+	return c.saveAnnotations()
+}
+
+func (c Full) saveAnnotations() (Full, tea.Cmd) {
+	err := storage.SaveGame(c.Key(), storage.GameData{Annotations: c.Annotations()})
+	if err != nil {
+		return c, tui.Notify(tui.SeverityError, "Could not save marks: "+err.Error())
+	}
+
+	return c, nil
 }
 
 func fieldWithinBoard(field string) bool {
@@ -257,3 +550,18 @@ func fieldWithinBoard(field string) bool {
 
 	return true
 }
+
+// keysInfo explains how to play with the keys the player has set.
+func (c Full) keysInfo() string {
+	keys := c.session.Keys
+
+	return fmt.Sprintf("To fire in your turn, aim at the enemy board (%s, %s, %s, %s) and press %s, or click the field. "+
+		"Press %s to type in the coordinate (i.e. A1) instead. If you hit, you can fire again.\n"+
+		"During the opponent's turn, %s (or %s on the board) queues the shot instead.\n"+
+		"Mark fields you haven't fired at with %s (suspected ship), %s (ruled out) or %s followed by any letter. %s removes the mark.\n"+
+		"Press %s to leave the game, or %s to see all keys.",
+		keys.Board.Up.Help().Key, keys.Board.Down.Help().Key, keys.Board.Left.Help().Key, keys.Board.Right.Help().Key,
+		keys.Game.Fire.Help().Key, keys.Game.Focus.Help().Key, keys.Game.Fire.Help().Key, keys.Game.Queue.Help().Key,
+		keys.Game.Suspected.Help().Key, keys.Game.RuledOut.Help().Key, keys.Game.Mark.Help().Key, keys.Game.Unmark.Help().Key,
+		keys.Game.Leave.Help().Key, keys.Global.Help.Help().Key)
+}
@@ -0,0 +1,132 @@
+package board
+
+import (
+	battleships "github.com/kovansky/wp-battleships"
+	"github.com/kovansky/wp-battleships/parts"
+	"math/rand"
+	"time"
+)
+
+// bestGuess picks the field to fire at when the player does not. In order of preference: queued shots,
+// fields marked as suspected, fields next to a hit ship that is not sunk yet, and finally a random field
+// which cannot be ruled out.
+func (c Full) bestGuess() (string, bool) {
+	board := c.OpponentBoard()
+	annotations := c.Annotations()
+
+	free := func(field string) bool {
+		_, shot := board[field]
+		return !shot
+	}
+
+	sunkRing, hunting := analyzeHits(board)
+
+	for _, field := range c.queue {
+		if free(field) && !sunkRing[field] {
+			return field, true
+		}
+	}
+
+	for field, annotation := range annotations {
+		if annotation == battleships.AnnotationSuspected && free(field) {
+			return field, true
+		}
+	}
+
+	for _, field := range hunting {
+		if free(field) && !sunkRing[field] {
+			return field, true
+		}
+	}
+
+	var candidates, fallback []string
+	for numeric := uint8(0); numeric < 100; numeric++ {
+		field, _ := parts.NumericToIdentifier(numeric)
+		if !free(field) {
+			continue
+		}
+
+		fallback = append(fallback, field)
+		if !sunkRing[field] && annotations[field] != battleships.AnnotationRuledOut {
+			candidates = append(candidates, field)
+		}
+	}
+
+	if len(candidates) == 0 {
+		candidates = fallback
+	}
+	if len(candidates) == 0 {
+		return "", false
+	}
+
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	return candidates[rng.Intn(len(candidates))], true
+}
+
+// analyzeHits groups hit fields into ships. Returns the fields around sunk ships, where no other ship
+// can be, and the fields next to ships that are hit, but not sunk yet.
+func analyzeHits(board map[string]battleships.FieldState) (map[string]bool, []string) {
+	var (
+		sunkRing = make(map[string]bool)
+		hunting  []string
+		visited  = make(map[string]bool)
+	)
+
+	isHit := func(field string) bool {
+		state, shot := board[field]
+		return shot && (state == battleships.FieldStateHit || state == battleships.FieldStateSunk)
+	}
+
+	for field := range board {
+		if visited[field] || !isHit(field) {
+			continue
+		}
+
+		// Walk through all hit fields connected by edges
+		var (
+			group []parts.Field
+			sunk  bool
+			stack = []string{field}
+		)
+		visited[field] = true
+		for len(stack) > 0 {
+			current := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+			f, err := parts.NewField(current)
+			if err != nil {
+				continue
+			}
+
+			group = append(group, f)
+			if board[current] == battleships.FieldStateSunk {
+				sunk = true
+			}
+
+			for _, direction := range []string{"N", "S", "W", "E"} {
+				next, ok := f.Adjacent()[direction]
+				if ok && !visited[next] && isHit(next) {
+					visited[next] = true
+					stack = append(stack, next)
+				}
+			}
+		}
+
+		for _, f := range group {
+			for direction, adjacent := range f.Adjacent() {
+				if parts.IsFieldIdentifier(direction) {
+					continue
+				}
+
+				if sunk {
+					sunkRing[adjacent] = true
+				} else if len(direction) == 1 {
+					hunting = append(hunting, adjacent)
+				}
+			}
+		}
+	}
+
+	return sunkRing, hunting
+}
@@ -0,0 +1,44 @@
+package board
+
+import (
+	battleships "github.com/kovansky/wp-battleships"
+	"testing"
+)
+
+func TestAnalyzeHits(t *testing.T) {
+	board := map[string]battleships.FieldState{
+		// Sunk two-masted ship
+		"A1": battleships.FieldStateHit,
+		"A2": battleships.FieldStateSunk,
+		// Ship that is hit, but not sunk
+		"E5":  battleships.FieldStateHit,
+		"F5":  battleships.FieldStateHit,
+		"J10": battleships.FieldStateMiss,
+	}
+
+	sunkRing, hunting := analyzeHits(board)
+
+	for _, field := range []string{"B1", "B2", "B3", "A3"} {
+		if !sunkRing[field] {
+			t.Fatalf("Field %s next to the sunk ship has not been ruled out", field)
+		}
+	}
+	if sunkRing["E4"] {
+		t.Fatalf("Field next to a not sunk ship has been ruled out")
+	}
+
+	expected := map[string]bool{"D5": true, "G5": true, "E4": true, "E6": true, "F4": true, "F6": true}
+	for _, field := range hunting {
+		if _, isHit := board[field]; isHit {
+			continue
+		}
+
+		if !expected[field] {
+			t.Fatalf("Unexpected hunting field %s", field)
+		}
+		delete(expected, field)
+	}
+	if len(expected) != 0 {
+		t.Fatalf("Missing hunting fields: %v", expected)
+	}
+}
@@ -0,0 +1,98 @@
+package board
+
+import (
+	"fmt"
+	"github.com/charmbracelet/lipgloss"
+	battleships "github.com/kovansky/wp-battleships"
+)
+
+// Number of shots listed at once in the history panel.
+const (
+	historyHeight        = 8
+	compactHistoryHeight = 4
+)
+
+func (c Full) historyHeight() int {
+	if c.layout() == layoutWide {
+		return historyHeight
+	}
+
+	return compactHistoryHeight
+}
+
+// scrollHistory moves the list of shots back by the given number of shots, or forward if it's negative.
+func (c Full) scrollHistory(shots int) Full {
+	c.historyScroll += shots
+
+	if last := len(c.History()) - c.historyHeight(); c.historyScroll > last {
+		c.historyScroll = last
+	}
+	if c.historyScroll < 0 {
+		c.historyScroll = 0
+	}
+
+	return c
+}
+
+// keepHistoryScrolled keeps the shots in place when a new one is added while the player is looking back.
+func (c Full) keepHistoryScrolled() Full {
+	if c.historyScroll > 0 {
+		c.historyScroll++
+	}
+
+	return c
+}
+
+// historyView lists the shots of both players, the newest at the bottom.
+func (c Full) historyView() string {
+	shots := c.History()
+	title := c.themes.global.TextPrimary().Copy().Bold(true).Render("Shots")
+
+	if len(shots) == 0 {
+		return lipgloss.JoinVertical(lipgloss.Left, title, "none yet")
+	}
+
+	end := len(shots) - c.historyScroll
+	if end > len(shots) || end < 1 {
+		end = len(shots)
+	}
+	start := end - c.historyHeight()
+	if start < 0 {
+		start = 0
+	}
+
+	if start > 0 || end < len(shots) {
+		keys := c.session.Keys.Game
+		title += fmt.Sprintf(" %d-%d of %d (%s/%s to scroll)", start+1, end, len(shots), keys.HistoryUp.Help().Key, keys.HistoryDown.Help().Key)
+	}
+
+	lines := []string{title}
+	for _, shot := range shots[start:end] {
+		lines = append(lines, c.shotView(shot))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+func (c Full) shotView(shot battleships.Shot) string {
+	shooter, theme := "You", c.themes.enemy
+	if shot.Opponent {
+		shooter, theme = c.opponentName(), c.themes.friendly
+		if shooter == "" {
+			shooter = "Opponent"
+		}
+	}
+
+	return fmt.Sprintf("%s  %-10.10s %-3s %s%s", shot.Time.Format("15:04:05"), shooter, shot.Field, theme.RenderField(shot.Result), shot.Result)
+}
+
+// lastOpponentShot returns the field the opponent has fired at most recently.
+func lastOpponentShot(shots []battleships.Shot) string {
+	for i := len(shots) - 1; i >= 0; i-- {
+		if shots[i].Opponent {
+			return shots[i].Field
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,73 @@
+package board
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	battleships "github.com/kovansky/wp-battleships"
+	"github.com/kovansky/wp-battleships/ships"
+	presets "github.com/kovansky/wp-battleships/tui/themes"
+	"github.com/rs/zerolog"
+	"strings"
+	"testing"
+)
+
+func TestFull_History(t *testing.T) {
+	log := zerolog.Nop()
+	game := ships.NewGame("key", &log)
+	game.SetGameStatus(battleships.GameStatus{Status: battleships.StatusGameInProgress})
+	game.SetBoard(map[string]battleships.FieldState{"A1": battleships.FieldStateShip})
+
+	gameThemes, err := presets.Preset(presets.Default)
+	if err != nil {
+		t.Fatalf("Received unexpected error: %v", err)
+	}
+
+	session := battleships.NewSession(nil, gameThemes, battleships.DefaultSettings())
+	session.SetGame(game)
+
+	c := InitFull(session, "")
+	c.Init()
+	model, _ := c.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	c = model.(Full)
+
+	for _, field := range []string{"B1", "B2", "B3", "B4", "B5"} {
+		game.RecordShot(field, battleships.FieldStateMiss)
+		model, _ = c.Update(battleships.ShotFired{Field: field, Result: battleships.FieldStateMiss})
+		c = model.(Full)
+	}
+
+	game.RecordOpponentShots([]string{"A1"})
+	model, _ = c.Update(battleships.ShotReceived{Field: "A1", Result: battleships.FieldStateHit})
+	c = model.(Full)
+
+	if c.friendly.highlight != "A1" {
+		t.Fatalf("Incorrect field highlighted; expected: A1, got: %q", c.friendly.highlight)
+	}
+
+	tests := []struct {
+		name     string
+		key      tea.KeyMsg
+		shown    string
+		notShown string
+	}{
+		{"latest", tea.KeyMsg{}, "A1", "B2"},
+		{"scrolled back", tea.KeyMsg{Type: tea.KeyPgUp}, "B2", "A1"},
+		{"scrolled forward", tea.KeyMsg{Type: tea.KeyPgDown}, "A1", "B2"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			model, _ = c.Update(test.key)
+			c = model.(Full)
+
+			history := c.historyView()
+			if !strings.Contains(history, " "+test.shown+" ") || strings.Contains(history, " "+test.notShown+" ") {
+				t.Fatalf("Expected %s listed without %s, got:\n%s", test.shown, test.notShown, history)
+			}
+
+			if height := lipgloss.Height(c.View()); height > 24 {
+				t.Fatalf("The screen doesn't fit; expected at most 24 lines, got: %d", height)
+			}
+		})
+	}
+}
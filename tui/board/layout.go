@@ -0,0 +1,107 @@
+package board
+
+import (
+	"fmt"
+	"github.com/charmbracelet/lipgloss"
+	battleships "github.com/kovansky/wp-battleships"
+	"github.com/kovansky/wp-battleships/tui"
+)
+
+// layout is the way the game screen is arranged, depending on the size of the terminal.
+type layout int
+
+const (
+	// layoutWide shows the boards and the game info side by side, with the ASCII art headers
+	layoutWide layout = iota
+	// layoutStacked puts the boards one under another, next to the shortened game info
+	layoutStacked
+	// layoutSingle shows one board at a time, the player switches between them
+	layoutSingle
+)
+
+// stackedHeight fits both boards with their titles, the gap between them and the prompt below.
+const stackedHeight = 2*(boardHeight+1) + 1 + 3
+
+// infoGap separates the boards from the game info in the compact layouts.
+const infoGap = 3
+
+func layoutFor(width, height int) layout {
+	switch {
+	case !tui.Compact(width, height):
+		return layoutWide
+	case height >= stackedHeight:
+		return layoutStacked
+	default:
+		return layoutSingle
+	}
+}
+
+func (c Full) layout() layout {
+	return layoutFor(c.width, c.height)
+}
+
+// compactView arranges the screen for small terminals: plain titles, boards in one column and a short legend.
+func (c Full) compactView() string {
+	friendlyState, enemyState := c.titleStyles()
+
+	friendly := lipgloss.JoinVertical(lipgloss.Left, friendlyState.Copy().Bold(true).Render("Friendly"), c.friendly.View())
+	enemy := lipgloss.JoinVertical(lipgloss.Left, enemyState.Copy().Bold(true).Render("Enemy"), c.opponent.View())
+
+	boards := lipgloss.JoinVertical(lipgloss.Left, friendly, "", enemy)
+	if c.layout() == layoutSingle {
+		boards = enemy
+		if c.ownBoard {
+			boards = friendly
+		}
+	}
+
+	info := c.gameInfo()
+	if summary := c.fleetSummary(); summary != "" {
+		info += "\n\n" + summary
+	}
+	info += "\n\n" + c.historyView() + "\n\n" + c.legendLine() + "\n" + c.shortKeysInfo()
+	infoWidth := c.width - boardWidth
+	if infoWidth > infoGap {
+		info = lipgloss.NewStyle().Width(infoWidth).PaddingLeft(infoGap).Render(info)
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, lipgloss.JoinHorizontal(lipgloss.Top, boards, info), c.statusView())
+}
+
+// opponentOrigin returns where the opponent's board is drawn, or false if it's not on the screen.
+func (c Full) opponentOrigin() (x, y int, visible bool) {
+	switch c.layout() {
+	case layoutStacked:
+		// Below our board, its title and the gap, under its own title
+		return 0, boardHeight + 3, true
+	case layoutSingle:
+		return 0, 1, !c.ownBoard
+	}
+
+	// The second column of the second row
+	return c.flexbox.Row(1).Cell(0).GetWidth(), c.flexbox.Row(0).Cell(0).GetHeight(), true
+}
+
+// legendLine is the legend collapsed to a single line.
+func (c Full) legendLine() string {
+	return fmt.Sprintf("%s ship  %s hit  %s sunk  %s miss  %s suspected  %s ruled out",
+		c.themes.friendly.RenderShip(),
+		c.themes.friendly.RenderHit(),
+		c.themes.enemy.RenderSunk(),
+		c.themes.enemy.RenderMiss(),
+		c.themes.enemy.RenderAnnotation(battleships.AnnotationSuspected),
+		c.themes.enemy.RenderAnnotation(battleships.AnnotationRuledOut),
+	)
+}
+
+// shortKeysInfo points to the help instead of explaining all the keys.
+func (c Full) shortKeysInfo() string {
+	keys := c.session.Keys
+
+	info := fmt.Sprintf("Press %s to see all keys.", keys.Global.Help.Help().Key)
+	if c.layout() == layoutSingle {
+		info = fmt.Sprintf("Press %s to show the other board, %s to see all keys.", keys.Game.SwitchBoard.Help().Key, keys.Global.Help.Help().Key)
+	}
+
+	return info
+}
@@ -0,0 +1,65 @@
+package board
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	battleships "github.com/kovansky/wp-battleships"
+	"github.com/kovansky/wp-battleships/ships"
+	presets "github.com/kovansky/wp-battleships/tui/themes"
+	"github.com/rs/zerolog"
+	"testing"
+)
+
+func TestFull_Layout(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height int
+		layout        layout
+	}{
+		{"wide", 160, 50, layoutWide},
+		{"narrow", 100, 50, layoutStacked},
+		{"tmux split", 120, 30, layoutStacked},
+		{"80x24", 80, 24, layoutSingle},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			log := zerolog.Nop()
+			game := ships.NewGame("key", &log)
+			game.SetGameStatus(battleships.GameStatus{Status: battleships.StatusGameInProgress})
+
+			gameThemes, err := presets.Preset(presets.Default)
+			if err != nil {
+				t.Fatalf("Received unexpected error: %v", err)
+			}
+
+			session := battleships.NewSession(nil, gameThemes, battleships.DefaultSettings())
+			session.SetGame(game)
+
+			c := InitFull(session, "")
+			c.Init()
+			model, _ := c.Update(tea.WindowSizeMsg{Width: test.width, Height: test.height})
+			c = model.(Full)
+
+			if c.layout() != test.layout {
+				t.Fatalf("Incorrect layout; expected: %d, got: %d", test.layout, c.layout())
+			}
+
+			if test.layout == layoutWide {
+				return
+			}
+
+			if height := lipgloss.Height(c.View()); height > test.height {
+				t.Fatalf("The screen doesn't fit; expected at most %d lines, got: %d", test.height, height)
+			}
+
+			// Clicking the top-left field of the opponent's board aims at it
+			x, y, _ := c.opponentOrigin()
+			model, _ = c.Update(tea.MouseMsg{Type: tea.MouseLeft, X: x + labelWidth, Y: y})
+			c = model.(Full)
+			if c.opponent.Cursor() != "A10" {
+				t.Fatalf("Incorrect field aimed at; expected: A10, got: %s", c.opponent.Cursor())
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package board
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kovansky/wp-battleships/tui"
+)
+
+// updateLeave handles the answer to whether to leave the game. Leaving the game abandons it.
+func (c Full) updateLeave(msg tea.KeyMsg) (Full, tea.Cmd) {
+	keys := c.session.Keys.Confirm
+
+	switch {
+	case key.Matches(msg, keys.Yes):
+		c.confirmLeave = false
+		return c, c.next(tui.NextGameLobby)
+	case key.Matches(msg, keys.No):
+		c.confirmLeave = false
+	}
+
+	return c, nil
+}
+
+func (c Full) leaveView() string {
+	keys := c.session.Keys.Confirm
+
+	return c.themes.global.TextSecondary().Render("Leave the game? You will lose it.") + "   " + tui.KeyOptions(keys.Yes, keys.No)
+}
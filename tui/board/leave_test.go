@@ -0,0 +1,83 @@
+package board
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	battleships "github.com/kovansky/wp-battleships"
+	"github.com/kovansky/wp-battleships/ships"
+	"github.com/kovansky/wp-battleships/tui"
+	presets "github.com/kovansky/wp-battleships/tui/themes"
+	"github.com/rs/zerolog"
+	"testing"
+)
+
+func TestFull_Leave(t *testing.T) {
+	tests := []struct {
+		name     string
+		answer   tea.Msg
+		answered bool
+		leave    bool
+	}{
+		{"yes", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}}, true, true},
+		{"no", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}}, true, false},
+		{"esc", tea.KeyMsg{Type: tea.KeyEsc}, true, false},
+		// Enter fires, so it doesn't answer the question by accident
+		{"enter", tea.KeyMsg{Type: tea.KeyEnter}, false, false},
+		// The click lands on the opponent's board
+		{"click", tea.MouseMsg{Type: tea.MouseLeft}, false, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			log := zerolog.Nop()
+			game := ships.NewGame("key", &log)
+			game.SetGameStatus(battleships.GameStatus{Status: battleships.StatusGameInProgress, ShouldFire: true})
+
+			gameThemes, err := presets.Preset(presets.Default)
+			if err != nil {
+				t.Fatalf("Received unexpected error: %v", err)
+			}
+
+			client := &stubClient{}
+			session := battleships.NewSession(client, gameThemes, battleships.DefaultSettings())
+			session.SetGame(game)
+
+			model, _ := InitFull(session, "").Update(tea.WindowSizeMsg{Width: 100, Height: 50})
+			model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+			c := model.(Full)
+			if !c.confirmLeave {
+				t.Fatalf("Expected the player to be asked before leaving")
+			}
+
+			answer := test.answer
+			if click, ok := answer.(tea.MouseMsg); ok {
+				x, y, _ := c.opponentOrigin()
+				click.X, click.Y = x+labelWidth, y
+				answer = click
+			}
+
+			model, cmd := c.Update(answer)
+			c = model.(Full)
+			if c.confirmLeave == test.answered {
+				t.Fatalf("Incorrect state of the question; expected answered: %v", test.answered)
+			}
+
+			if len(client.fired) > 0 {
+				t.Fatalf("Fired behind the question at %v", client.fired)
+			}
+
+			if !test.leave {
+				if cmd != nil {
+					t.Fatalf("Expected to stay in the game")
+				}
+				return
+			}
+
+			if cmd == nil {
+				t.Fatalf("Expected to leave the game")
+			}
+			if msg, ok := cmd().(tui.NextGameMsg); !ok || msg.Choice != tui.NextGameLobby {
+				t.Fatalf("Incorrect message; expected: lobby, got: %+v", msg)
+			}
+		})
+	}
+}
@@ -1,19 +1,27 @@
 package board
 
 import (
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	battleships "github.com/kovansky/wp-battleships"
 	"github.com/kovansky/wp-battleships/parts"
+	"github.com/kovansky/wp-battleships/tui"
 	"strings"
 )
 
 type NewSingle struct {
 	theme  battleships.Theme
+	keys   battleships.BoardKeys
 	fields map[string]parts.State
+
+	// cursor is the numeric representation of the highlighted field (see parts.Field.Numeric)
+	cursor  uint8
+	focused bool
 }
 
-func InitNewSingle(theme battleships.Theme, board map[string]parts.State) NewSingle {
-	return NewSingle{theme: theme, fields: board}
+func InitNewSingle(theme battleships.Theme, keys battleships.BoardKeys, board map[string]parts.State) NewSingle {
+	// Start in the top-left corner (A10)
+	return NewSingle{theme: theme, keys: keys, fields: board, cursor: 9}
 }
 
 func (c *NewSingle) Init() tea.Cmd {
@@ -23,15 +31,64 @@ func (c *NewSingle) Init() tea.Cmd {
 func (c *NewSingle) Update(msg tea.Msg) (NewSingle, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c":
-			return *c, tea.Quit
+		if !c.focused {
+			break
+		}
+
+		switch {
+		case key.Matches(msg, c.keys.Up):
+			if c.cursor%10 < 9 {
+				c.cursor++
+			}
+		case key.Matches(msg, c.keys.Down):
+			if c.cursor%10 > 0 {
+				c.cursor--
+			}
+		case key.Matches(msg, c.keys.Left):
+			if c.cursor >= 10 {
+				c.cursor -= 10
+			}
+		case key.Matches(msg, c.keys.Right):
+			if c.cursor < 90 {
+				c.cursor += 10
+			}
 		}
 	}
 
 	return *c, nil
 }
 
+func (c *NewSingle) SetTheme(theme battleships.Theme) {
+	c.theme = theme
+}
+
+func (c *NewSingle) Focus() {
+	c.focused = true
+}
+
+func (c *NewSingle) Blur() {
+	c.focused = false
+}
+
+func (c *NewSingle) Focused() bool {
+	return c.focused
+}
+
+// Cursor returns the identifier of the highlighted field.
+func (c *NewSingle) Cursor() string {
+	identifier, _ := parts.NumericToIdentifier(c.cursor)
+	return identifier
+}
+
+func (c *NewSingle) SetCursor(field string) {
+	f, err := parts.NewField(field)
+	if err != nil {
+		return
+	}
+
+	c.cursor = f.Numeric()
+}
+
 func (c *NewSingle) SetBoard(board map[string]parts.State) {
 	c.fields = board
 }
@@ -54,12 +111,22 @@ func (c *NewSingle) View() string {
 		for _, colLabel := range cols {
 			field := colLabel + rowLabel
 
+			if c.focused && field == c.Cursor() {
+				state, contains := c.fields[field]
+				if !contains {
+					state = parts.FieldEmpty
+				}
+
+				builder.WriteString(c.theme.NewRenderCursor(state))
+				continue
+			}
+
 			if state, contains := c.fields[field]; contains {
 				builder.WriteString(c.theme.NewRenderField(state))
 				continue
 			}
 
-			builder.WriteString(strings.Repeat(sep, 2))
+			builder.WriteString(strings.Repeat(sep, tui.CellWidth))
 		}
 
 		builder.WriteByte('\n')
@@ -67,7 +134,7 @@ func (c *NewSingle) View() string {
 
 	builder.WriteString(strings.Repeat(sep, 3))
 	for _, colLabel := range cols {
-		builder.WriteString(c.theme.Cols().Render(colLabel) + sep)
+		builder.WriteString(c.theme.Cols().Render(colLabel) + strings.Repeat(sep, tui.CellWidth-1))
 	}
 
 	return builder.String()
@@ -0,0 +1,106 @@
+package board
+
+import (
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	battleships "github.com/kovansky/wp-battleships"
+	"github.com/kovansky/wp-battleships/tui"
+	"strings"
+)
+
+// queuedShotMsg fires the next queued shot.
+type queuedShotMsg struct{}
+
+func fireQueued() tea.Msg {
+	return queuedShotMsg{}
+}
+
+// toggleQueued adds the field at the end of the shots queue, or removes it if it's already queued.
+func (c Full) toggleQueued(field string) (Full, tea.Cmd) {
+	if _, shot := c.OpponentBoard()[field]; shot {
+		return c, tui.Notify(tui.SeverityWarning, "You already fired at this field!")
+	}
+
+	if queue := removeField(c.queue, field); len(queue) != len(c.queue) {
+		c.queue = queue
+		return c, nil
+	}
+
+	c.queue = append(c.queue[:len(c.queue):len(c.queue)], field)
+
+	return c, nil
+}
+
+// pruneQueue drops queued fields which got resolved in the meantime, either shot or next to a sunk ship.
+func (c Full) pruneQueue() Full {
+	board := c.OpponentBoard()
+	sunkRing, _ := analyzeHits(board)
+	queue := make([]string, 0, len(c.queue))
+
+	for _, field := range c.queue {
+		if _, shot := board[field]; !shot && !sunkRing[field] {
+			queue = append(queue, field)
+		}
+	}
+
+	c.queue = queue
+
+	return c
+}
+
+// startQueue begins firing the queued shots, if it's our turn.
+func (c Full) startQueue() (Full, tea.Cmd) {
+	c = c.pruneQueue()
+
+	if c.firingQueue || len(c.queue) == 0 ||
+		c.GameStatus().Status != battleships.StatusGameInProgress || !c.GameStatus().ShouldFire {
+		return c, nil
+	}
+
+	c.firingQueue = true
+
+	return c, fireQueued
+}
+
+// fireNextQueued fires the first queued shot. We keep going after a hit, and stop after a miss.
+func (c Full) fireNextQueued() (Full, tea.Cmd) {
+	c = c.pruneQueue()
+
+	if len(c.queue) == 0 || !c.GameStatus().ShouldFire {
+		c.firingQueue = false
+		return c, nil
+	}
+
+	field := c.queue[0]
+	c.queue = c.queue[1:]
+
+	var cmd tea.Cmd
+	c, cmd = c.fire(field)
+
+	state, shot := c.OpponentBoard()[field]
+	if !shot || state == battleships.FieldStateMiss || len(c.queue) == 0 || !c.GameStatus().ShouldFire {
+		c.firingQueue = false
+		return c, cmd
+	}
+
+	return c, tea.Batch(cmd, fireQueued)
+}
+
+func (c Full) queueInfo() string {
+	if len(c.queue) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("\n\nQueued shots: %s\n\t(fired when your turn begins, Q to clear)",
+		c.themes.global.TextPrimary().Render(strings.Join(c.queue, ", ")))
+}
+
+func removeField(fields []string, field string) []string {
+	for i, f := range fields {
+		if f == field {
+			return append(fields[:i:i], fields[i+1:]...)
+		}
+	}
+
+	return fields
+}
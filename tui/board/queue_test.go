@@ -0,0 +1,125 @@
+package board
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	battleships "github.com/kovansky/wp-battleships"
+	"github.com/kovansky/wp-battleships/ships"
+	"github.com/kovansky/wp-battleships/tui"
+	"github.com/rs/zerolog"
+	"testing"
+)
+
+// stubClient answers shots from a predefined list of results, and takes the turn away after a miss.
+// Like the real client, it updates the status of the game after each shot.
+type stubClient struct {
+	battleships.Client
+
+	results map[string]battleships.ShotState
+	fired   []string
+	// status is what the server reports after the shots, if set
+	status *battleships.GameStatus
+}
+
+func (s *stubClient) Fire(game battleships.Game, field string) (battleships.ShotState, error) {
+	s.fired = append(s.fired, field)
+
+	result, ok := s.results[field]
+	if !ok {
+		result = battleships.ShotMiss
+	}
+
+	if s.status != nil {
+		game.SetGameStatus(*s.status)
+	}
+
+	if result == battleships.ShotMiss {
+		status := game.GameStatus()
+		status.ShouldFire = false
+		game.SetGameStatus(status)
+	}
+
+	return result, nil
+}
+
+func TestFull_Queue(t *testing.T) {
+	log := zerolog.Nop()
+
+	client := &stubClient{results: map[string]battleships.ShotState{
+		"A1": battleships.ShotHit,
+		"A2": battleships.ShotSunk,
+	}}
+	game := ships.NewGame("", &log)
+	game.SetGameStatus(battleships.GameStatus{Status: battleships.StatusGameInProgress})
+	game.SetOpponentBoard(map[string]battleships.FieldState{"J10": battleships.FieldStateMiss})
+
+	theme := tui.NewTheme()
+	session := battleships.NewSession(client, battleships.GameThemes{Player: theme, Enemy: theme, Global: theme}, battleships.DefaultSettings())
+	session.SetGame(game)
+
+	c := InitFull(session, "")
+
+	for _, field := range []string{"A1", "J10", "A2", "B5", "C5", "B5", "D5"} {
+		c, _ = c.toggleQueued(field)
+	}
+
+	// J10 was already shot, B5 was toggled off
+	expected := []string{"A1", "A2", "C5", "D5"}
+	if len(c.queue) != len(expected) {
+		t.Fatalf("Incorrect queue; expected: %v, got: %v", expected, c.queue)
+	}
+
+	_, notify := c.toggleQueued("J10")
+	if notify == nil {
+		t.Fatalf("Expected a notification about the field already shot")
+	}
+	if msg, ok := notify().(tui.NotifyMsg); !ok || msg.Severity != tui.SeverityWarning {
+		t.Fatalf("Incorrect message; expected: warning, got: %+v", msg)
+	}
+
+	// A2 gets resolved in the meantime
+	game.RecordShot("A2", battleships.FieldStateMiss)
+
+	game.SetGameStatus(battleships.GameStatus{Status: battleships.StatusGameInProgress, ShouldFire: true})
+
+	var cmd tea.Cmd
+	for c, cmd = c.startQueue(); cmd != nil; {
+		c, cmd = c.fireNextQueued()
+	}
+
+	// A1 hits, so we go on to C5, which misses - D5 waits for the next turn
+	if len(client.fired) != 2 || client.fired[0] != "A1" || client.fired[1] != "C5" {
+		t.Fatalf("Incorrect shots fired; got: %v", client.fired)
+	}
+	if len(c.queue) != 1 || c.queue[0] != "D5" {
+		t.Fatalf("Incorrect queue left; got: %v", c.queue)
+	}
+	if c.firingQueue {
+		t.Fatalf("Queue is still marked as being fired")
+	}
+}
+
+func TestFull_PruneQueue(t *testing.T) {
+	log := zerolog.Nop()
+
+	game := ships.NewGame("", &log)
+	game.SetOpponentBoard(map[string]battleships.FieldState{
+		"E5": battleships.FieldStateSunk,
+		"E6": battleships.FieldStateSunk,
+		"A1": battleships.FieldStateHit,
+	})
+
+	theme := tui.NewTheme()
+	session := battleships.NewSession(&stubClient{}, battleships.GameThemes{Player: theme, Enemy: theme, Global: theme}, battleships.DefaultSettings())
+	session.SetGame(game)
+
+	c := InitFull(session, "")
+	c.queue = []string{"E5", "D5", "F7", "E4", "A2", "H8"}
+
+	// E5 is shot, D5, F7 and E4 are next to the sunk ship, A2 is next to a ship which is not sunk yet
+	c = c.pruneQueue()
+
+	expected := []string{"A2", "H8"}
+	if len(c.queue) != len(expected) || c.queue[0] != expected[0] || c.queue[1] != expected[1] {
+		t.Fatalf("Incorrect queue; expected: %v, got: %v", expected, c.queue)
+	}
+}
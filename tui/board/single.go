@@ -1,18 +1,42 @@
 package board
 
 import (
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	battleships "github.com/kovansky/wp-battleships"
+	"github.com/kovansky/wp-battleships/parts"
+	"github.com/kovansky/wp-battleships/tui"
 	"strings"
 )
 
+// Dimensions of the rendered board: row labels with separator, and a glyph with padding per field.
+// The ten rows are followed by the column labels.
+const (
+	labelWidth  = 3
+	fieldWidth  = tui.CellWidth
+	boardWidth  = labelWidth + 10*fieldWidth
+	boardHeight = 11
+)
+
 type Single struct {
-	theme  battleships.Theme
-	fields map[string]battleships.FieldState
+	theme       battleships.Theme
+	keys        battleships.BoardKeys
+	fields      map[string]battleships.FieldState
+	annotations map[string]battleships.Annotation
+
+	// cursor is the numeric representation of the highlighted field (see parts.Field.Numeric)
+	cursor  uint8
+	focused bool
+
+	// highlight is the field drawn like the cursor, to point it out, e.g. the opponent's last shot
+	highlight string
+	// overlay holds the rendered cells drawn instead of the fields, e.g. the frames of animations
+	overlay map[string]string
 }
 
-func InitSingle(theme battleships.Theme, board map[string]battleships.FieldState) Single {
-	return Single{theme: theme, fields: board}
+func InitSingle(theme battleships.Theme, keys battleships.BoardKeys, board map[string]battleships.FieldState) Single {
+	// Start in the top-left corner (A10)
+	return Single{theme: theme, keys: keys, fields: board, cursor: 9}
 }
 
 func (c *Single) Init() tea.Cmd {
@@ -22,15 +46,94 @@ func (c *Single) Init() tea.Cmd {
 func (c *Single) Update(msg tea.Msg) (Single, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c":
-			return *c, tea.Quit
+		if !c.focused {
+			break
+		}
+
+		switch {
+		case key.Matches(msg, c.keys.Up):
+			if c.cursor%10 < 9 {
+				c.cursor++
+			}
+		case key.Matches(msg, c.keys.Down):
+			if c.cursor%10 > 0 {
+				c.cursor--
+			}
+		case key.Matches(msg, c.keys.Left):
+			if c.cursor >= 10 {
+				c.cursor -= 10
+			}
+		case key.Matches(msg, c.keys.Right):
+			if c.cursor < 90 {
+				c.cursor += 10
+			}
 		}
 	}
 
 	return *c, nil
 }
 
+func (c *Single) SetTheme(theme battleships.Theme) {
+	c.theme = theme
+}
+
+// SetAnnotations sets the player's marks, which are shown on fields that have not been shot at yet.
+func (c *Single) SetAnnotations(annotations map[string]battleships.Annotation) {
+	c.annotations = annotations
+}
+
+func (c *Single) Focus() {
+	c.focused = true
+}
+
+func (c *Single) Blur() {
+	c.focused = false
+}
+
+func (c *Single) Focused() bool {
+	return c.focused
+}
+
+// Cursor returns the identifier of the highlighted field.
+func (c *Single) Cursor() string {
+	identifier, _ := parts.NumericToIdentifier(c.cursor)
+	return identifier
+}
+
+func (c *Single) SetCursor(field string) {
+	f, err := parts.NewField(field)
+	if err != nil {
+		return
+	}
+
+	c.cursor = f.Numeric()
+}
+
+// FieldAt translates a position relative to the top-left corner of the rendered board into a field identifier.
+func (c *Single) FieldAt(x, y int) (string, bool) {
+	if y < 0 || y > 9 || x < labelWidth || x >= labelWidth+10*fieldWidth {
+		return "", false
+	}
+
+	column := uint8((x - labelWidth) / fieldWidth)
+	row := uint8(9 - y)
+
+	identifier, err := parts.NumericToIdentifier(column*10 + row)
+	if err != nil {
+		return "", false
+	}
+
+	return identifier, true
+}
+
+func (c *Single) SetHighlight(field string) {
+	c.highlight = field
+}
+
+func (c *Single) SetOverlay(overlay map[string]string) {
+	c.overlay = overlay
+}
+
 func (c *Single) SetBoard(board map[string]battleships.FieldState) {
 	c.fields = board
 }
@@ -53,12 +156,39 @@ func (c *Single) View() string {
 		for _, colLabel := range cols {
 			field := colLabel + rowLabel
 
-			if state, contains := c.fields[field]; contains {
+			state, contains := c.fields[field]
+			annotation, annotated := c.annotations[field]
+
+			if cell, ok := c.overlay[field]; ok {
+				builder.WriteString(cell)
+				continue
+			}
+
+			if c.focused && field == c.Cursor() {
+				if !contains && annotated {
+					builder.WriteString(c.theme.RenderAnnotationCursor(annotation))
+				} else {
+					builder.WriteString(c.theme.RenderCursor(state))
+				}
+				continue
+			}
+
+			if contains && field == c.highlight {
+				builder.WriteString(c.theme.RenderCursor(state))
+				continue
+			}
+
+			if contains {
 				builder.WriteString(c.theme.RenderField(state))
 				continue
 			}
 
-			builder.WriteString(strings.Repeat(sep, 2))
+			if annotated {
+				builder.WriteString(c.theme.RenderAnnotation(annotation))
+				continue
+			}
+
+			builder.WriteString(strings.Repeat(sep, tui.CellWidth))
 		}
 
 		builder.WriteByte('\n')
@@ -66,7 +196,7 @@ func (c *Single) View() string {
 
 	builder.WriteString(strings.Repeat(sep, 3))
 	for _, colLabel := range cols {
-		builder.WriteString(c.theme.Cols().Render(colLabel) + sep)
+		builder.WriteString(c.theme.Cols().Render(colLabel) + strings.Repeat(sep, tui.CellWidth-1))
 	}
 
 	return builder.String()
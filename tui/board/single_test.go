@@ -0,0 +1,45 @@
+package board
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	battleships "github.com/kovansky/wp-battleships"
+	"github.com/kovansky/wp-battleships/tui"
+	"strings"
+	"testing"
+)
+
+func TestSingle_ViewAlignment(t *testing.T) {
+	tests := []struct {
+		name  string
+		glyph string
+	}{
+		{"ascii", "X"},
+		{"symbol", "●"},
+		{"emoji", "💥"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			theme := tui.NewTheme().
+				SetHit(tui.NewBrush().SetChar(test.glyph)).
+				SetMiss(tui.NewBrush().SetChar("o"))
+
+			single := InitSingle(theme, battleships.DefaultKeyMap().Board, map[string]battleships.FieldState{
+				"A10": battleships.FieldStateHit,
+				"B10": battleships.FieldStateMiss,
+				"J1":  battleships.FieldStateHit,
+			})
+			single.Focus()
+			single.SetCursor("A10")
+
+			lines := strings.Split(single.View(), "\n")
+			expected := labelWidth + 10*fieldWidth
+
+			for i, line := range lines {
+				if width := lipgloss.Width(line); width != expected {
+					t.Fatalf("Line %d is misaligned; expected width: %d, got: %d (%q)", i, expected, width, line)
+				}
+			}
+		})
+	}
+}
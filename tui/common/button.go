@@ -27,7 +27,11 @@ func (c Button) Init() tea.Cmd {
 	return nil
 }
 
-func (c Button) Update(_ tea.Msg) (tea.Model, tea.Cmd) {
+func (c Button) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(battleships.ThemesChangeMsg); ok {
+		c.theme = msg.Themes.Global
+	}
+
 	return c, nil
 }
 
@@ -4,6 +4,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	battleships "github.com/kovansky/wp-battleships"
+	"github.com/kovansky/wp-battleships/tui"
 	"github.com/mbndr/figlet4go"
 )
 
@@ -16,6 +17,9 @@ type Header struct {
 	width  int
 	Height int
 
+	// compact renders the text as it is, the ASCII art doesn't fit in small terminals
+	compact bool
+
 	asciiRender *figlet4go.AsciiRender
 }
 
@@ -39,25 +43,33 @@ func (c Header) Init() tea.Cmd {
 
 func (c Header) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case battleships.ThemesChangeMsg:
+		c.theme = msg.Themes.Global
+		c.blockStyle = c.theme.TextPrimary().
+			Copy().
+			Width(c.width).
+			Align(lipgloss.Center).
+			PaddingBottom(2)
 	case tea.WindowSizeMsg:
 		c.width = msg.Width
+		c.compact = tui.Compact(msg.Width, msg.Height)
 		c.blockStyle = c.theme.TextPrimary().
 			Copy().
 			Width(c.width).
 			Align(lipgloss.Center).
 			PaddingBottom(2)
 
-		ascii, _ := c.asciiRender.Render(c.Text)
-
-		render := c.blockStyle.Render(ascii)
-
-		c.Height = lipgloss.Height(render)
+		c.Height = lipgloss.Height(c.View())
 	}
 
 	return c, nil
 }
 
 func (c Header) View() string {
+	if c.compact {
+		return c.blockStyle.Copy().Bold(true).PaddingBottom(1).Render(c.Text)
+	}
+
 	ascii, _ := c.asciiRender.Render(c.Text)
 
 	render := c.blockStyle.Render(ascii)
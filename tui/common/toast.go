@@ -0,0 +1,133 @@
+package common
+
+import (
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	battleships "github.com/kovansky/wp-battleships"
+	"github.com/kovansky/wp-battleships/tui"
+	"time"
+)
+
+const (
+	// maxToasts is the number of notifications shown at once, the oldest give way to the new ones
+	maxToasts = 3
+	// historySize is the number of notifications kept for the history
+	historySize = 50
+)
+
+// toastDurations is how long the notifications of each severity stay on the screen.
+var toastDurations = map[tui.Severity]time.Duration{
+	tui.SeverityInfo:    3 * time.Second,
+	tui.SeverityWarning: 5 * time.Second,
+	tui.SeverityError:   8 * time.Second,
+}
+
+// ToastExpiredMsg hides the notification, once its time is up.
+type ToastExpiredMsg struct {
+	ID int
+}
+
+type toast struct {
+	tui.NotifyMsg
+
+	id   int
+	time time.Time
+}
+
+// Toasts shows the notifications stacked, the newest at the bottom, and keeps them for the history.
+type Toasts struct {
+	theme battleships.Theme
+
+	shown   []toast
+	history []toast
+	lastID  int
+}
+
+func CreateToasts(theme battleships.Theme) Toasts {
+	return Toasts{
+		theme: theme,
+	}
+}
+
+func (c Toasts) Init() tea.Cmd {
+	return nil
+}
+
+func (c Toasts) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tui.NotifyMsg:
+		c.lastID++
+		t := toast{NotifyMsg: msg, id: c.lastID, time: time.Now()}
+
+		// The slices are shared with the previous copies of the model, so they are never changed in place
+		c.shown = lastToasts(append(append([]toast(nil), c.shown...), t), maxToasts)
+		c.history = lastToasts(append(append([]toast(nil), c.history...), t), historySize)
+
+		return c, tea.Tick(toastDurations[msg.Severity], func(time.Time) tea.Msg {
+			return ToastExpiredMsg{ID: t.id}
+		})
+	case ToastExpiredMsg:
+		var shown []toast
+		for _, t := range c.shown {
+			if t.id != msg.ID {
+				shown = append(shown, t)
+			}
+		}
+		c.shown = shown
+	case battleships.ThemesChangeMsg:
+		c.theme = msg.Themes.Global
+	}
+
+	return c, nil
+}
+
+func lastToasts(toasts []toast, count int) []toast {
+	if len(toasts) > count {
+		return toasts[len(toasts)-count:]
+	}
+
+	return toasts
+}
+
+func (c Toasts) View() string {
+	if len(c.shown) == 0 {
+		return ""
+	}
+
+	lines := make([]string, len(c.shown))
+	for i, t := range c.shown {
+		lines[i] = c.toastView(t)
+	}
+
+	return lipgloss.NewStyle().Padding(0, 1).Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
+
+// HistoryView lists the notifications shown so far, the newest first.
+func (c Toasts) HistoryView() string {
+	lines := []string{c.theme.TextPrimary().Copy().Bold(true).Render("Notifications"), ""}
+
+	if len(c.history) == 0 {
+		lines = append(lines, "none yet")
+	}
+
+	for i := len(c.history) - 1; i >= 0; i-- {
+		t := c.history[i]
+		lines = append(lines, t.time.Format("15:04:05")+" "+c.toastView(t))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// toastView labels the notification with its severity, so it can be told apart without colours too.
+func (c Toasts) toastView(t toast) string {
+	style := c.theme.TextPrimary()
+	switch t.Severity {
+	case tui.SeverityWarning:
+		style = c.theme.TextSecondary()
+	case tui.SeverityError:
+		style = c.theme.TextSecondary().Copy().Bold(true)
+	}
+
+	return style.Render(fmt.Sprintf("[%s]", t.Severity)) + " " + t.Message
+}
@@ -0,0 +1,62 @@
+package common
+
+import (
+	"github.com/kovansky/wp-battleships/tui"
+	presets "github.com/kovansky/wp-battleships/tui/themes"
+	"strings"
+	"testing"
+)
+
+func TestToasts_Update(t *testing.T) {
+	tests := []struct {
+		name     string
+		messages []string
+		expired  []int
+		shown    []string
+		history  []string
+	}{
+		{"single", []string{"a"}, nil, []string{"a"}, []string{"a"}},
+		{"stacked", []string{"a", "b", "c", "d"}, nil, []string{"b", "c", "d"}, []string{"a", "b", "c", "d"}},
+		{"expired", []string{"a", "b", "c"}, []int{2}, []string{"a", "c"}, []string{"a", "b", "c"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			gameThemes, err := presets.Preset(presets.Default)
+			if err != nil {
+				t.Fatalf("Received unexpected error: %v", err)
+			}
+			toasts := CreateToasts(gameThemes.Global)
+
+			for _, message := range test.messages {
+				model, cmd := toasts.Update(tui.NotifyMsg{Severity: tui.SeverityInfo, Message: message})
+				if cmd == nil {
+					t.Fatalf("Expected the notification to expire")
+				}
+				toasts = model.(Toasts)
+			}
+
+			for _, id := range test.expired {
+				model, _ := toasts.Update(ToastExpiredMsg{ID: id})
+				toasts = model.(Toasts)
+			}
+
+			if received := messages(toasts.shown); strings.Join(received, ",") != strings.Join(test.shown, ",") {
+				t.Fatalf("Expected shown: %v, got: %v", test.shown, received)
+			}
+
+			if received := messages(toasts.history); strings.Join(received, ",") != strings.Join(test.history, ",") {
+				t.Fatalf("Expected history: %v, got: %v", test.history, received)
+			}
+		})
+	}
+}
+
+func messages(toasts []toast) []string {
+	var received []string
+	for _, t := range toasts {
+		received = append(received, t.Message)
+	}
+
+	return received
+}
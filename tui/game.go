@@ -0,0 +1,31 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	battleships "github.com/kovansky/wp-battleships"
+)
+
+// BotNick is the name the server gives its own player.
+const BotNick = "WP_Bot"
+
+// InitGame creates a game of the player on the server, against the opponent if there is one.
+// Creating the game is not retried, the server may have created it even if the response got lost.
+// The returned error lets the player decide whether to try again.
+func InitGame(session *battleships.Session, opponent string, retry tea.Cmd) (battleships.Game, *ErrorMsg) {
+	gamePost := session.Player().GamePost()
+
+	switch opponent {
+	case "":
+	case BotNick:
+		gamePost.Wpbot = true
+	default:
+		gamePost.TargetNick = opponent
+	}
+
+	game, err := session.Client.InitGame(gamePost)
+	if err != nil {
+		return nil, &ErrorMsg{Message: "Couldn't initialize game", Err: err, Persistent: true, Retry: retry}
+	}
+
+	return game, nil
+}
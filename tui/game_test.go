@@ -0,0 +1,64 @@
+package tui_test
+
+import (
+	"errors"
+	battleships "github.com/kovansky/wp-battleships"
+	"github.com/kovansky/wp-battleships/tui"
+	"testing"
+)
+
+// initClient records the games it's asked to create.
+type initClient struct {
+	battleships.Client
+
+	err   error
+	posts []battleships.GamePost
+}
+
+func (c *initClient) InitGame(data battleships.GamePost) (battleships.Game, error) {
+	c.posts = append(c.posts, data)
+
+	return nil, c.err
+}
+
+func TestInitGame(t *testing.T) {
+	tests := []struct {
+		name     string
+		opponent string
+		expected battleships.GamePost
+	}{
+		{"anyone", "", battleships.GamePost{Nick: "player", Coords: []string{"A1"}}},
+		{"bot", tui.BotNick, battleships.GamePost{Nick: "player", Coords: []string{"A1"}, Wpbot: true}},
+		{"player", "opponent", battleships.GamePost{Nick: "player", Coords: []string{"A1"}, TargetNick: "opponent"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := &initClient{}
+			session := battleships.NewSession(client, battleships.GameThemes{}, battleships.DefaultSettings())
+			session.SetPlayer(battleships.PlayerData{Nick: "player", Board: []string{"A1"}})
+
+			if _, errMsg := tui.InitGame(session, test.opponent, nil); errMsg != nil {
+				t.Fatalf("Received unexpected error: %v", errMsg.Err)
+			}
+
+			post := client.posts[0]
+			if post.Nick != test.expected.Nick || post.TargetNick != test.expected.TargetNick ||
+				post.Wpbot != test.expected.Wpbot || len(post.Coords) != 1 {
+				t.Fatalf("Incorrect game; expected: %+v, got: %+v", test.expected, post)
+			}
+		})
+	}
+
+	// The game may have been created even though the request failed, so it's not sent again
+	client := &initClient{err: errors.New("connection reset")}
+	session := battleships.NewSession(client, battleships.GameThemes{}, battleships.DefaultSettings())
+
+	_, errMsg := tui.InitGame(session, "", nil)
+	if errMsg == nil || !errMsg.Persistent {
+		t.Fatalf("Expected a persistent error, got: %+v", errMsg)
+	}
+	if len(client.posts) != 1 {
+		t.Fatalf("Expected a single request, got: %d", len(client.posts))
+	}
+}
@@ -0,0 +1,57 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/charmbracelet/bubbles/key"
+	battleships "github.com/kovansky/wp-battleships"
+	"strings"
+)
+
+// KeyOptions lists the bindings as choices for the player, e.g. "[r] retry   [q] quit".
+func KeyOptions(bindings ...key.Binding) string {
+	var options []string
+	for _, binding := range bindings {
+		if !binding.Enabled() {
+			continue
+		}
+
+		options = append(options, fmt.Sprintf("[%s] %s", binding.Help().Key, binding.Help().Desc))
+	}
+
+	return strings.Join(options, "   ")
+}
+
+// StageHelp groups the key bindings available in the stage into columns, the global ones last.
+func StageHelp(keys battleships.KeyMap, stage Stage) [][]key.Binding {
+	global := []key.Binding{keys.Global.Help, keys.Global.Theme, keys.Global.Notifications, keys.Global.Debug, keys.Global.Quit}
+	if _, canGoBack := stageBackTargets[stage]; canGoBack {
+		global = append(global, keys.Global.Back)
+	}
+
+	var columns [][]key.Binding
+
+	switch stage {
+	case StageLogin:
+		columns = [][]key.Binding{{keys.Login.Next, keys.Login.Previous, keys.Login.Submit}}
+	case StageSetup:
+		columns = [][]key.Binding{
+			{keys.Setup.Focus, keys.Setup.Submit, keys.Setup.Undo, keys.Setup.Redo},
+			{keys.Board.Up, keys.Board.Down, keys.Board.Left, keys.Board.Right},
+			{keys.Setup.ShipClass, keys.Setup.Rotate, keys.Setup.Place, keys.Setup.PickUp, keys.Setup.Fill},
+		}
+	case StageLobby:
+		columns = [][]key.Binding{{keys.Lobby.Up, keys.Lobby.Down, keys.Lobby.Challenge, keys.Lobby.ClearFilter, keys.Lobby.Ranking}}
+	case StageRanking:
+		columns = [][]key.Binding{{keys.Ranking.Up, keys.Ranking.Down, keys.Ranking.Lobby}}
+	case StageGame:
+		columns = [][]key.Binding{
+			{keys.Board.Up, keys.Board.Down, keys.Board.Left, keys.Board.Right},
+			{keys.Game.Focus, keys.Game.Fire, keys.Game.Queue, keys.Game.ClearQueue, keys.Game.Leave},
+			{keys.Game.Suspected, keys.Game.RuledOut, keys.Game.Mark, keys.Game.Unmark, keys.Game.SwitchBoard},
+			{keys.Game.HistoryUp, keys.Game.HistoryDown},
+			{keys.End.Rematch, keys.End.PlayAgain, keys.End.Lobby, keys.End.Setup},
+		}
+	}
+
+	return append(columns, global)
+}
@@ -0,0 +1,13 @@
+package tui
+
+// Below these sizes of the terminal (in cells), the screens switch to the compact layout: without the big ASCII art
+// headers, with the boards stacked and the explanations shortened.
+const (
+	CompactWidth  = 120
+	CompactHeight = 35
+)
+
+// Compact tells if the screens should use the compact layout in the terminal of the given size.
+func Compact(width, height int) bool {
+	return width < CompactWidth || height < CompactHeight
+}
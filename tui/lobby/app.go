@@ -2,6 +2,7 @@ package lobby
 
 import (
 	"context"
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	battleships "github.com/kovansky/wp-battleships"
@@ -12,7 +13,8 @@ import (
 )
 
 type Lobby struct {
-	log zerolog.Logger
+	log     zerolog.Logger
+	session *battleships.Session
 
 	theme battleships.Theme
 
@@ -22,15 +24,16 @@ type Lobby struct {
 	asciiRender *figlet4go.AsciiRender
 }
 
-func Create(ctx context.Context, theme battleships.Theme, initialPlayers []battleships.Player) Lobby {
+func Create(ctx context.Context, session *battleships.Session, theme battleships.Theme, initialPlayers []battleships.Player) Lobby {
 	asciiRender := figlet4go.NewAsciiRender()
 	header := common.CreateHeader("Battleships", theme, asciiRender)
-	table := CreatePlayers(ctx, theme, initialPlayers)
+	table := CreatePlayers(ctx, session, theme, initialPlayers)
 
 	log := ctx.Value(battleships.ContextKeyLog).(zerolog.Logger)
 
 	return Lobby{
 		log:         log,
+		session:     session,
 		theme:       theme,
 		asciiRender: asciiRender,
 		subcomponents: map[string]tea.Model{
@@ -59,17 +62,17 @@ func (c Lobby) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c":
-			return c, tea.Quit
-		case "R":
+		switch {
+		case key.Matches(msg, c.session.Keys.Lobby.Ranking):
 			return c, func() tea.Msg {
 				return tui.ApplicationStageChangeMsg{
-					From:  tui.StageLogin,
+					From:  tui.StageLobby,
 					Stage: tui.StageRanking,
 				}
 			}
 		}
+	case battleships.ThemesChangeMsg:
+		c.theme = msg.Themes.Global
 	case tea.WindowSizeMsg:
 		for name, cmp := range c.subcomponents {
 			c.subcomponents[name], cmd = cmp.Update(msg)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/76creates/stickers"
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	battleships "github.com/kovansky/wp-battleships"
@@ -14,7 +15,8 @@ import (
 )
 
 type Players struct {
-	log zerolog.Logger
+	log     zerolog.Logger
+	session *battleships.Session
 
 	theme battleships.Theme
 
@@ -30,7 +32,7 @@ type Players struct {
 	table *stickers.Table
 }
 
-func CreatePlayers(ctx context.Context, theme battleships.Theme, initialPlayers []battleships.Player) Players {
+func CreatePlayers(ctx context.Context, session *battleships.Session, theme battleships.Theme, initialPlayers []battleships.Player) Players {
 	log := ctx.Value(battleships.ContextKeyLog).(zerolog.Logger)
 
 	table, err := initializeTable(theme, initialPlayers...)
@@ -39,9 +41,10 @@ func CreatePlayers(ctx context.Context, theme battleships.Theme, initialPlayers
 	}
 
 	return Players{
-		log:   log,
-		theme: theme,
-		table: table,
+		log:     log,
+		session: session,
+		theme:   theme,
+		table:   table,
 	}
 }
 
@@ -52,65 +55,22 @@ func (c Players) Init() tea.Cmd {
 func (c Players) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c":
-			return c, tea.Quit
-		case "down":
+		keys := c.session.Keys.Lobby
+
+		switch {
+		case key.Matches(msg, keys.Down):
 			c.table.CursorDown()
 			_, c.focused = c.table.GetCursorLocation()
-		case "up":
+		case key.Matches(msg, keys.Up):
 			c.table.CursorUp()
 			_, c.focused = c.table.GetCursorLocation()
-		case "enter", " ":
+		case key.Matches(msg, keys.Challenge):
 			c.selected = c.table.GetCursorValue()
 
-			var (
-				game     battleships.Game
-				gamePost battleships.GamePost
-				err      error
-			)
-			gamePost = battleships.GamePost{
-				Wpbot: false,
-			}
-			if len(battleships.PlayerData.Nick) > 0 {
-				gamePost.Nick = battleships.PlayerData.Nick
-				gamePost.Desc = battleships.PlayerData.Description
-			}
-			if len(battleships.PlayerData.Board) > 0 {
-				gamePost.Coords = battleships.PlayerData.Board
-			}
-			if c.selected == "WP_Bot" {
-				gamePost.Wpbot = true
-			} else {
-				gamePost.TargetNick = c.selected
-			}
-
-			game, err = battleships.ServerClient.InitGame(gamePost)
-			if err != nil {
-				c.log.Fatal().Err(err).Msg("Couldn't initialize game")
-			}
-
-			err = battleships.ServerClient.UpdateBoard(game)
-			if err != nil {
-				c.log.Fatal().Err(err).Msg("Couldn't update the game board")
-			}
-			err = battleships.ServerClient.GameStatus(game)
-			if err != nil {
-				c.log.Fatal().Err(err).Msg("Couldn't update the game status")
-			}
-
-			battleships.GameInstance = game
-
-			gameBoard := board.InitFull(battleships.GameInstance, battleships.Themes.Player, battleships.Themes.Enemy, battleships.Themes.Global, fmt.Sprintf(lipgloss.NewStyle().Italic(true).Render("Waiting for game...")))
-			return c, func() tea.Msg {
-				return tui.ApplicationStageChangeMsg{
-					From:  tui.StageLobby,
-					Stage: tui.StageGame,
-					Model: gameBoard,
-				}
-			}
-		case "backspace":
-			c.filterWithStr(msg.String())
+			return c, c.challenge(c.selected)
+		case key.Matches(msg, keys.ClearFilter):
+			// filterWithStr erases the last character of the filter on backspace
+			c.filterWithStr("backspace")
 			_, c.filterString = c.table.GetFilter()
 		default:
 			if len(msg.String()) == 1 {
@@ -121,6 +81,9 @@ func (c Players) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 		}
+	case battleships.ThemesChangeMsg:
+		c.theme = msg.Themes.Global
+		c.table.SetStyles(tableStyles(c.theme))
 	case tea.WindowSizeMsg:
 		c.table.
 			SetWidth(c.Width).
@@ -145,6 +108,44 @@ func (c Players) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return c, nil
 }
 
+// challenge starts a game with the selected player. Network calls are retried, and if they keep failing,
+// the error is reported to the player with an option to try again.
+func (c Players) challenge(opponent string) tea.Cmd {
+	var cmd tea.Cmd
+
+	cmd = func() tea.Msg {
+		game, errMsg := tui.InitGame(c.session, opponent, cmd)
+		if errMsg != nil {
+			return *errMsg
+		}
+
+		err := battleships.Retry(tui.RetryAttempts, tui.RetryDelay, func() error {
+			return c.session.Client.UpdateBoard(game)
+		})
+		if err != nil {
+			return tui.ErrorMsg{Message: "Couldn't update the game board", Err: err, Persistent: true, Retry: cmd}
+		}
+		err = battleships.Retry(tui.RetryAttempts, tui.RetryDelay, func() error {
+			return c.session.Client.GameStatus(game)
+		})
+		if err != nil {
+			return tui.ErrorMsg{Message: "Couldn't update the game status", Err: err, Persistent: true, Retry: cmd}
+		}
+
+		c.session.SetGame(game)
+
+		gameBoard := board.InitFull(c.session, fmt.Sprintf(lipgloss.NewStyle().Italic(true).Render("Waiting for game...")))
+
+		return tui.ApplicationStageChangeMsg{
+			From:  tui.StageLobby,
+			Stage: tui.StageGame,
+			Model: gameBoard,
+		}
+	}
+
+	return cmd
+}
+
 func (c Players) View() string {
 	return c.table.Render()
 }
@@ -165,12 +166,7 @@ func initializeTable(theme battleships.Theme, players ...battleships.Player) (*s
 	table, _ = table.SetTypes([]any{s, i, i, i}...)
 	table.SetRatio([]int{6, 2, 1, 1}).SetMinWidth([]int{10, 4, 3, 3})
 
-	table.SetStyles(map[stickers.TableStyleKey]lipgloss.Style{
-		stickers.TableCellCursorStyleKey: lipgloss.NewStyle().
-			Background(theme.TextPrimary().GetForeground()).
-			Foreground(lipgloss.Color("#383838")),
-		stickers.TableRowsCursorStyleKey: lipgloss.NewStyle(),
-	})
+	table.SetStyles(tableStyles(theme))
 
 	var (
 		playersTable [][]any
@@ -186,6 +182,15 @@ func initializeTable(theme battleships.Theme, players ...battleships.Player) (*s
 	return table, err
 }
 
+func tableStyles(theme battleships.Theme) map[stickers.TableStyleKey]lipgloss.Style {
+	return map[stickers.TableStyleKey]lipgloss.Style{
+		stickers.TableCellCursorStyleKey: lipgloss.NewStyle().
+			Background(theme.TextPrimary().GetForeground()).
+			Foreground(lipgloss.Color("#383838")),
+		stickers.TableRowsCursorStyleKey: lipgloss.NewStyle(),
+	}
+}
+
 func (c Players) filterWithStr(key string) {
 	i, s := c.table.GetFilter()
 	x, _ := c.table.GetCursorLocation()
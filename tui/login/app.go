@@ -2,6 +2,7 @@ package login
 
 import (
 	"context"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -16,8 +17,9 @@ import (
 )
 
 type Login struct {
-	ctx context.Context
-	log zerolog.Logger
+	ctx     context.Context
+	log     zerolog.Logger
+	session *battleships.Session
 
 	theme battleships.Theme
 
@@ -29,7 +31,7 @@ type Login struct {
 	asciiRender *figlet4go.AsciiRender
 }
 
-func Create(ctx context.Context, theme battleships.Theme) Login {
+func Create(ctx context.Context, session *battleships.Session, theme battleships.Theme) Login {
 	log := ctx.Value(battleships.ContextKeyLog).(zerolog.Logger)
 
 	asciiRender := figlet4go.NewAsciiRender()
@@ -70,10 +72,11 @@ func Create(ctx context.Context, theme battleships.Theme) Login {
 	submitButton := common.CreateButton("Submit", theme)
 
 	return Login{
-		ctx:    ctx,
-		log:    log,
-		theme:  theme,
-		inputs: inputComponents,
+		ctx:     ctx,
+		log:     log,
+		session: session,
+		theme:   theme,
+		inputs:  inputComponents,
 		subcomponents: map[string]tea.Model{
 			"header": header,
 			"submit": submitButton,
@@ -95,19 +98,17 @@ func (c Login) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c":
-			return c, tea.Quit
-		case "tab", "shift+tab", "enter", "up", "down":
-			s := msg.String()
+		keys := c.session.Keys.Login
 
+		switch {
+		case key.Matches(msg, keys.Next, keys.Previous, keys.Submit):
 			// Is user trying to submit the whole thing?
-			if s == "enter" && c.focusIndex == len(c.inputs) {
+			if key.Matches(msg, keys.Submit) && c.focusIndex == len(c.inputs) {
 				return c, c.submit()
 			}
 
 			// Is user trying to move up/down?
-			if s == "up" || s == "shift+tab" {
+			if key.Matches(msg, keys.Previous) {
 				c.focusIndex--
 			} else {
 				c.focusIndex++
@@ -139,6 +140,10 @@ func (c Login) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	}
 
+	if msg, ok := msg.(battleships.ThemesChangeMsg); ok {
+		c.theme = msg.Themes.Global
+	}
+
 	for name, cmp := range c.subcomponents {
 		c.subcomponents[name], cmd = cmp.Update(msg)
 		cmds = append(cmds, cmd)
@@ -191,8 +196,9 @@ func (c Login) View() string {
 }
 
 func (c Login) submit() tea.Cmd {
-	battleships.PlayerData.Nick = c.inputs[0].Value()
-	battleships.PlayerData.Description = c.inputs[1].Value()
+	player := c.session.Player()
+	player.Nick = c.inputs[0].Value()
+	player.Description = c.inputs[1].Value()
 	mode := c.inputs[2].Value()
 	wantsSetup := false
 
@@ -200,10 +206,11 @@ func (c Login) submit() tea.Cmd {
 		wantsSetup = true
 	}
 
-	battleships.PlayerData.PlayMode = battleships.PlayModeWait
+	player.PlayMode = battleships.PlayModeWait
 	if mode == "l" {
-		battleships.PlayerData.PlayMode = battleships.PlayModeChallenge
+		player.PlayMode = battleships.PlayModeChallenge
 	}
+	c.session.SetPlayer(player)
 
 	var targetStage tui.Stage
 	if wantsSetup {
@@ -214,52 +221,51 @@ func (c Login) submit() tea.Cmd {
 		targetStage = tui.StageWait
 	}
 
-	var app tea.Model
-
-	switch targetStage {
-	case tui.StageSetup:
-		app = setup.Create(c.ctx, c.theme)
-		break
-	case tui.StageLobby:
-		players, err := battleships.ServerClient.ListPlayers()
-		if err != nil {
-			c.log.Fatal().Err(err).Msg("failed to list players")
-		}
+	return c.enterStage(targetStage)
+}
 
-		app = lobby.Create(c.ctx, battleships.Themes.Global, players)
-		break
-	default:
-		var (
-			game     battleships.Game
-			gamePost battleships.GamePost
-			err      error
-		)
-		gamePost = battleships.GamePost{
-			Wpbot: false,
-		}
-		if len(battleships.PlayerData.Nick) > 0 {
-			gamePost.Nick = battleships.PlayerData.Nick
-			gamePost.Desc = battleships.PlayerData.Description
-		}
-		if len(battleships.PlayerData.Board) > 0 {
-			gamePost.Coords = battleships.PlayerData.Board
-		}
+// enterStage prepares the model of the chosen stage. Network calls are retried, and if they keep failing,
+// the error is reported to the player with an option to try again.
+func (c Login) enterStage(targetStage tui.Stage) tea.Cmd {
+	var cmd tea.Cmd
+
+	cmd = func() tea.Msg {
+		var app tea.Model
+
+		switch targetStage {
+		case tui.StageSetup:
+			app = setup.Create(c.ctx, c.session, c.theme)
+			break
+		case tui.StageLobby:
+			var players []battleships.Player
+
+			err := battleships.Retry(tui.RetryAttempts, tui.RetryDelay, func() (err error) {
+				players, err = c.session.Client.ListPlayers()
+				return err
+			})
+			if err != nil {
+				return tui.ErrorMsg{Message: "Failed to list players", Err: err, Persistent: true, Retry: cmd}
+			}
 
-		game, err = battleships.ServerClient.InitGame(gamePost)
-		if err != nil {
-			c.log.Fatal().Err(err).Msg("Couldn't initialize game")
-		}
+			app = lobby.Create(c.ctx, c.session, c.session.Themes().Global, players)
+			break
+		default:
+			game, errMsg := tui.InitGame(c.session, "", cmd)
+			if errMsg != nil {
+				return *errMsg
+			}
 
-		battleships.GameInstance = game
+			c.session.SetGame(game)
 
-		app = wait.Create(c.ctx, c.theme)
-	}
+			app = wait.Create(c.ctx, c.theme)
+		}
 
-	return func() tea.Msg {
 		return tui.ApplicationStageChangeMsg{
 			From:  tui.StageLogin,
 			Stage: targetStage,
 			Model: app,
 		}
 	}
+
+	return cmd
 }
@@ -2,10 +2,89 @@ package tui
 
 import (
 	tea "github.com/charmbracelet/bubbletea"
+	"time"
 )
 
+// Network operations started by the player are retried before the error is reported.
+const (
+	RetryAttempts = 3
+	RetryDelay    = 500 * time.Millisecond
+)
+
+// ApplicationStageChangeMsg requests moving to another stage. Messages sent from a stage
+// the application has left in the meantime are ignored.
 type ApplicationStageChangeMsg struct {
 	From  Stage
 	Stage Stage
 	Model tea.Model
 }
+
+type NextGame int
+
+const (
+	// NextGameRematch challenges the same opponent again
+	NextGameRematch NextGame = iota
+	// NextGamePlayAgain waits for a new opponent, with the same board
+	NextGamePlayAgain
+	// NextGameLobby goes back to the lobby, it is also how a game in progress is left
+	NextGameLobby
+	NextGameSetup
+)
+
+// NextGameMsg carries the player's choice of what comes after the game has ended.
+type NextGameMsg struct {
+	Choice   NextGame
+	Opponent string
+}
+
+// Severity tells how important a notification is.
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	}
+
+	return "info"
+}
+
+// NotifyMsg shows a short notification to the player, which disappears on its own. Any stage or routine may send it.
+type NotifyMsg struct {
+	Severity Severity
+	Message  string
+}
+
+// Notify returns the command showing the notification.
+func Notify(severity Severity, message string) tea.Cmd {
+	return func() tea.Msg {
+		return NotifyMsg{Severity: severity, Message: message}
+	}
+}
+
+// ErrorMsg reports a failed operation. Transient errors are shown as notifications, persistent ones let the player
+// choose between retrying, going back to the lobby and quitting.
+type ErrorMsg struct {
+	Message    string
+	Err        error
+	Persistent bool
+
+	// Retry repeats the failed operation, nil if it cannot be repeated
+	Retry tea.Cmd
+}
+
+func (e ErrorMsg) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+
+	return e.Message + ": " + e.Err.Error()
+}
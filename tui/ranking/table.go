@@ -3,6 +3,7 @@ package ranking
 import (
 	"context"
 	"github.com/76creates/stickers"
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	battleships "github.com/kovansky/wp-battleships"
@@ -10,7 +11,8 @@ import (
 )
 
 type Table struct {
-	log zerolog.Logger
+	log     zerolog.Logger
+	session *battleships.Session
 
 	theme battleships.Theme
 
@@ -26,7 +28,7 @@ type Table struct {
 	table *stickers.Table
 }
 
-func CreateTable(ctx context.Context, theme battleships.Theme, initialRankingTable []battleships.Player) Table {
+func CreateTable(ctx context.Context, session *battleships.Session, theme battleships.Theme, initialRankingTable []battleships.Player) Table {
 	log := ctx.Value(battleships.ContextKeyLog).(zerolog.Logger)
 
 	table, err := initializeTable(theme, initialRankingTable...)
@@ -35,9 +37,10 @@ func CreateTable(ctx context.Context, theme battleships.Theme, initialRankingTab
 	}
 
 	return Table{
-		log:   log,
-		theme: theme,
-		table: table,
+		log:     log,
+		session: session,
+		theme:   theme,
+		table:   table,
 	}
 }
 
@@ -48,16 +51,17 @@ func (c Table) Init() tea.Cmd {
 func (c Table) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c":
-			return c, tea.Quit
-		case "down":
+		switch {
+		case key.Matches(msg, c.session.Keys.Ranking.Down):
 			c.table.CursorDown()
 			_, c.focused = c.table.GetCursorLocation()
-		case "up":
+		case key.Matches(msg, c.session.Keys.Ranking.Up):
 			c.table.CursorUp()
 			_, c.focused = c.table.GetCursorLocation()
 		}
+	case battleships.ThemesChangeMsg:
+		c.theme = msg.Themes.Global
+		c.table.SetStyles(tableStyles(c.theme))
 	case tea.WindowSizeMsg:
 		c.table.
 			SetWidth(c.Width).
@@ -88,12 +92,7 @@ func initializeTable(theme battleships.Theme, players ...battleships.Player) (*s
 	table, _ = table.SetTypes([]any{i, s, i, i, i}...)
 	table.SetRatio([]int{1, 6, 2, 1, 1}).SetMinWidth([]int{2, 10, 4, 3, 3})
 
-	table.SetStyles(map[stickers.TableStyleKey]lipgloss.Style{
-		stickers.TableCellCursorStyleKey: lipgloss.NewStyle().
-			Background(theme.TextPrimary().GetForeground()).
-			Foreground(lipgloss.Color("#383838")),
-		stickers.TableRowsCursorStyleKey: lipgloss.NewStyle(),
-	})
+	table.SetStyles(tableStyles(theme))
 
 	var (
 		RankingTableTable [][]any
@@ -109,6 +108,15 @@ func initializeTable(theme battleships.Theme, players ...battleships.Player) (*s
 	return table, err
 }
 
+func tableStyles(theme battleships.Theme) map[stickers.TableStyleKey]lipgloss.Style {
+	return map[stickers.TableStyleKey]lipgloss.Style{
+		stickers.TableCellCursorStyleKey: lipgloss.NewStyle().
+			Background(theme.TextPrimary().GetForeground()).
+			Foreground(lipgloss.Color("#383838")),
+		stickers.TableRowsCursorStyleKey: lipgloss.NewStyle(),
+	}
+}
+
 func (c Table) filterWithStr(key string) {
 	i, s := c.table.GetFilter()
 	x, _ := c.table.GetCursorLocation()
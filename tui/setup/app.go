@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -22,8 +23,9 @@ import (
 const SizeMax = 4
 
 type Setup struct {
-	ctx context.Context
-	log zerolog.Logger
+	ctx     context.Context
+	log     zerolog.Logger
+	session *battleships.Session
 
 	theme battleships.Theme
 
@@ -33,6 +35,12 @@ type Setup struct {
 	input textinput.Model
 	ships map[int][]parts.Ship
 
+	// Ship class and orientation used for cursor-driven placement
+	shipSize    int
+	orientation parts.Orientation
+
+	history history
+
 	errorText              string
 	protectedFields        map[string]parts.State
 	currentProtectedFields map[string]parts.State
@@ -43,19 +51,19 @@ type Setup struct {
 	asciiRender *figlet4go.AsciiRender
 }
 
-func Create(ctx context.Context, theme battleships.Theme) Setup {
+func Create(ctx context.Context, session *battleships.Session, theme battleships.Theme) Setup {
 	log := ctx.Value(battleships.ContextKeyLog).(zerolog.Logger)
 
 	asciiRender := figlet4go.NewAsciiRender()
 
 	header := common.CreateHeader("Battleships", theme, asciiRender)
-	b := board.InitNewSingle(battleships.Themes.Player, map[string]parts.State{})
+	b := board.InitNewSingle(session.Themes().Player, session.Keys.Board, map[string]parts.State{})
+	b.Focus()
 
 	input := textinput.New()
 	input.Placeholder = "Place next ship part"
-	input.CharLimit = 5
+	input.CharLimit = 10
 	input.Width = 25
-	input.Focus()
 
 	perSize := map[int]int{
 		1: 4,
@@ -68,22 +76,18 @@ func Create(ctx context.Context, theme battleships.Theme) Setup {
 		shipsLimit += lim
 	}
 
-	return Setup{
-		ctx:   ctx,
-		log:   log,
-		theme: theme,
+	setup := Setup{
+		ctx:     ctx,
+		log:     log,
+		session: session,
+		theme:   theme,
 		subcomponents: map[string]tea.Model{
 			"header": header,
 		},
-		board: b,
-		input: input,
-		ships: map[int][]parts.Ship{
-			0: make([]parts.Ship, 0, 1),
-			1: make([]parts.Ship, 0, perSize[1]),
-			2: make([]parts.Ship, 0, perSize[2]),
-			3: make([]parts.Ship, 0, perSize[3]),
-			4: make([]parts.Ship, 0, perSize[4]),
-		},
+		board:                  b,
+		input:                  input,
+		shipSize:               SizeMax,
+		ships:                  emptyShips(perSize),
 		shipsLimit:             shipsLimit,
 		shipsLimitPerSize:      perSize,
 		errorText:              "",
@@ -91,6 +95,19 @@ func Create(ctx context.Context, theme battleships.Theme) Setup {
 		currentProtectedFields: map[string]parts.State{},
 		asciiRender:            asciiRender,
 	}
+	setup.refreshBoard()
+
+	return setup
+}
+
+func emptyShips(perSize map[int]int) map[int][]parts.Ship {
+	return map[int][]parts.Ship{
+		0: make([]parts.Ship, 0, 1),
+		1: make([]parts.Ship, 0, perSize[1]),
+		2: make([]parts.Ship, 0, perSize[2]),
+		3: make([]parts.Ship, 0, perSize[3]),
+		4: make([]parts.Ship, 0, perSize[4]),
+	}
 }
 
 func (c Setup) Init() tea.Cmd {
@@ -105,8 +122,27 @@ func (c Setup) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "enter":
+		keys := c.session.Keys.Setup
+
+		switch {
+		case key.Matches(msg, keys.Focus):
+			return c.toggleFocus()
+		case key.Matches(msg, keys.Undo):
+			c = c.undo()
+			c.refreshBoard()
+			return c, nil
+		case key.Matches(msg, keys.Redo):
+			c = c.redo()
+			c.refreshBoard()
+			return c, nil
+		}
+
+		if c.board.Focused() {
+			return c.updateCursor(msg)
+		}
+
+		switch {
+		case key.Matches(msg, keys.Submit):
 			c.errorText = ""
 			value := c.input.Value()
 
@@ -115,36 +151,76 @@ func (c Setup) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return c, nil
 			}
 
-			switch strings.ToLower(value) {
+			command, argument, _ := strings.Cut(strings.ToLower(value), " ")
+
+			switch command {
 			case "ok", "next":
-				newC := c.finishShip()
-				newC.board.SetBoard(newC.protectedFields)
+				newC := c.change(Setup.finishShip)
+				newC.refreshBoard()
 
 				newC.input.SetValue("")
 
 				return newC, nil
 			case "start":
 				return c.finishSetup()
-			default:
-				newC := c.addShip(value)
+			case "undo":
+				newC := c.undo()
+				newC.refreshBoard()
 
-				fullBoard := map[string]parts.State{}
-				for k, v := range newC.protectedFields {
-					fullBoard[k] = v
+				newC.input.SetValue("")
+
+				return newC, nil
+			case "redo":
+				newC := c.redo()
+				newC.refreshBoard()
+
+				newC.input.SetValue("")
+
+				return newC, nil
+			case "fill":
+				newC := c.change(Setup.fill)
+				newC.refreshBoard()
+
+				if len(newC.errorText) == 0 {
+					newC.input.SetValue("")
 				}
-				for k, v := range newC.currentProtectedFields {
-					fullBoard[k] = v
+
+				return newC, nil
+			case "clear":
+				newC := c.change(Setup.clear)
+				newC.refreshBoard()
+
+				newC.input.SetValue("")
+
+				return newC, nil
+			case "del", "delete", "rm", "remove":
+				field := strings.ToUpper(strings.TrimSpace(argument))
+				newC := c.change(func(c Setup) Setup {
+					return c.deleteShip(field)
+				})
+				newC.refreshBoard()
+
+				if len(newC.errorText) == 0 {
+					newC.input.SetValue("")
 				}
 
-				newC.board.SetBoard(fullBoard)
+				return newC, nil
+			default:
+				newC := c.change(func(c Setup) Setup {
+					return c.addShip(value)
+				})
+				newC.refreshBoard()
 
 				return newC, nil
 			}
-		case "ctrl+c":
-			return c, tea.Quit
 		}
 	}
 
+	if msg, ok := msg.(battleships.ThemesChangeMsg); ok {
+		c.theme = msg.Themes.Global
+		c.board.SetTheme(msg.Themes.Player)
+	}
+
 	c.board, cmd = c.board.Update(msg)
 	cmds = append(cmds, cmd)
 	c.input, cmd = c.input.Update(msg)
@@ -159,6 +235,8 @@ func (c Setup) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (c Setup) View() string {
+	keys := c.session.Keys
+
 	layout := lipgloss.JoinVertical(lipgloss.Center,
 		c.subcomponents["header"].View(),
 
@@ -170,11 +248,13 @@ func (c Setup) View() string {
 					"* One-masted: %d/%d\n"+
 					"* Two-masted: %d/%d\n"+
 					"* Three-masted: %d/%d\n"+
-					"* Four-masted: %d/%d\n",
+					"* Four-masted: %d/%d\n"+
+					"\nSelected: %s, %s\n",
 					len(c.ships[1]), cap(c.ships[1]),
 					len(c.ships[2]), cap(c.ships[2]),
 					len(c.ships[3]), cap(c.ships[3]),
 					len(c.ships[4]), cap(c.ships[4]),
+					shipClassNames[c.shipSize], c.orientation,
 				),
 			),
 		),
@@ -192,8 +272,18 @@ func (c Setup) View() string {
 	layout = lipgloss.JoinVertical(lipgloss.Center,
 		layout,
 		"\n\n\n",
+		fmt.Sprintf("%s to move, %s to pick ship class, %s to rotate",
+			strings.Join([]string{keys.Board.Up.Help().Key, keys.Board.Down.Help().Key, keys.Board.Left.Help().Key, keys.Board.Right.Help().Key}, ", "),
+			keys.Setup.ShipClass.Help().Key, keys.Setup.Rotate.Help().Key),
+		fmt.Sprintf("%s to place ship, %s to pick it back up, %s to fill the rest",
+			keys.Setup.Place.Help().Key, keys.Setup.PickUp.Help().Key, keys.Setup.Fill.Help().Key),
+		fmt.Sprintf("%s to switch between the board and typing", keys.Setup.Focus.Help().Key),
+		"",
 		"type in field identifiers to place ships",
 		"ok/next to submit ship",
+		"fill to randomly place the remaining ships",
+		"del <field> to remove a ship, clear to remove all",
+		fmt.Sprintf("undo/redo (%s/%s) to step through changes", keys.Setup.Undo.Help().Key, keys.Setup.Redo.Help().Key),
 		"start to save board",
 	)
 
@@ -230,57 +320,60 @@ func (c Setup) finishSetup() (Setup, tea.Cmd) {
 		}
 	}
 
-	battleships.PlayerData.Board = shipsBoard
+	player := c.session.Player()
+	player.Board = shipsBoard
+	c.session.SetPlayer(player)
 
 	var targetStage tui.Stage
 	targetStage = tui.StageWait
-	if battleships.PlayerData.PlayMode == battleships.PlayModeChallenge {
+	if player.PlayMode == battleships.PlayModeChallenge {
 		targetStage = tui.StageLobby
 	}
 
-	var app tea.Model
-	switch targetStage {
-	case tui.StageLobby:
-		players, err := battleships.ServerClient.ListPlayers()
-		if err != nil {
-			c.log.Fatal().Err(err).Msg("failed to list players")
-		}
+	return c, c.enterStage(targetStage)
+}
 
-		app = lobby.Create(c.ctx, battleships.Themes.Global, players)
-		break
-	default:
-		var (
-			game     battleships.Game
-			gamePost battleships.GamePost
-			err      error
-		)
+// enterStage prepares the model of the chosen stage. Network calls are retried, and if they keep failing,
+// the error is reported to the player with an option to try again.
+func (c Setup) enterStage(targetStage tui.Stage) tea.Cmd {
+	var cmd tea.Cmd
 
-		gamePost = battleships.GamePost{
-			Wpbot:  false,
-			Coords: battleships.PlayerData.Board,
-		}
-		if len(battleships.PlayerData.Nick) > 0 {
-			gamePost.Nick = battleships.PlayerData.Nick
-			gamePost.Desc = battleships.PlayerData.Description
-		}
+	cmd = func() tea.Msg {
+		var app tea.Model
 
-		game, err = battleships.ServerClient.InitGame(gamePost)
-		if err != nil {
-			c.log.Fatal().Err(err).Msg("Couldn't initialize game")
-		}
+		switch targetStage {
+		case tui.StageLobby:
+			var players []battleships.Player
 
-		battleships.GameInstance = game
+			err := battleships.Retry(tui.RetryAttempts, tui.RetryDelay, func() (err error) {
+				players, err = c.session.Client.ListPlayers()
+				return err
+			})
+			if err != nil {
+				return tui.ErrorMsg{Message: "Failed to list players", Err: err, Persistent: true, Retry: cmd}
+			}
 
-		app = wait.Create(c.ctx, c.theme)
-	}
+			app = lobby.Create(c.ctx, c.session, c.session.Themes().Global, players)
+			break
+		default:
+			game, errMsg := tui.InitGame(c.session, "", cmd)
+			if errMsg != nil {
+				return *errMsg
+			}
+
+			c.session.SetGame(game)
+
+			app = wait.Create(c.ctx, c.theme)
+		}
 
-	return c, func() tea.Msg {
 		return tui.ApplicationStageChangeMsg{
 			From:  tui.StageSetup,
 			Stage: targetStage,
 			Model: app,
 		}
 	}
+
+	return cmd
 }
 
 func (c Setup) finishShip() Setup {
@@ -313,6 +406,13 @@ func (c Setup) finishShip() Setup {
 	c.ships[0] = make([]parts.Ship, 0, 1)
 	c.currentProtectedFields = map[string]parts.State{}
 
+	c.protectShip(ship)
+
+	return c
+}
+
+// protectShip marks the ship and its surroundings in protectedFields.
+func (c Setup) protectShip(ship parts.Ship) {
 	var (
 		stateHit    parts.State = parts.FieldHit
 		hitPriority             = stateHit.Priority()
@@ -322,15 +422,11 @@ func (c Setup) finishShip() Setup {
 		// Or if it's here as a protected (edge) - overwrite
 		if current, contains := c.protectedFields[f]; !contains || current.Priority() < hitPriority {
 			c.protectedFields[f] = stateHit
-			if err != nil {
-				c.errorText = "error while saving ship"
-				return c
-			}
 		}
 	}
 	protected, err := ship.Protected()
 	if err != nil {
-		return c
+		return
 	}
 	for identifier, f := range protected {
 		// If field is currently denoted empty
@@ -339,10 +435,69 @@ func (c Setup) finishShip() Setup {
 			c.protectedFields[identifier] = f.State
 		}
 	}
+}
+
+// protectCurrent marks the ship being built and its surroundings in currentProtectedFields.
+func (c *Setup) protectCurrent(ship parts.Ship) {
+	c.currentProtectedFields = map[string]parts.State{}
+
+	var stateShip parts.State = parts.FieldShip
+	shipPriority := stateShip.Priority()
+	for f := range ship.Ship() {
+		if current, contains := c.currentProtectedFields[f]; !contains || current.Priority() < shipPriority {
+			c.currentProtectedFields[f] = stateShip
+		}
+	}
+	protected, err := ship.Protected()
+	if err != nil {
+		return
+	}
+	for identifier, f := range protected {
+		c.currentProtectedFields[identifier] = f.State
+
+		if _, fullContains := c.protectedFields[identifier]; ship.Size() < SizeMax &&
+			f.State == parts.FieldProtected &&
+			!fullContains {
+			c.currentProtectedFields[identifier] = parts.FieldPotential
+		}
+	}
+}
+
+// rebuildProtected recomputes protectedFields from scratch, using all finished ships.
+func (c Setup) rebuildProtected() Setup {
+	c.protectedFields = map[string]parts.State{}
+
+	for size, shipsOfSize := range c.ships {
+		if size == 0 {
+			continue
+		}
+
+		for _, ship := range shipsOfSize {
+			c.protectShip(ship)
+		}
+	}
 
 	return c
 }
 
+// refreshBoard composes finished ships, the ship being built and the placement preview onto the board.
+func (c *Setup) refreshBoard() {
+	fullBoard := map[string]parts.State{}
+	for k, v := range c.protectedFields {
+		fullBoard[k] = v
+	}
+	for k, v := range c.currentProtectedFields {
+		fullBoard[k] = v
+	}
+	if c.board.Focused() {
+		for k, v := range c.preview() {
+			fullBoard[k] = v
+		}
+	}
+
+	c.board.SetBoard(fullBoard)
+}
+
 func (c Setup) addShip(value string) Setup {
 	var (
 		ship parts.Ship
@@ -395,28 +550,7 @@ func (c Setup) addShip(value string) Setup {
 		return c
 	}
 
-	c.currentProtectedFields = map[string]parts.State{}
-
-	var stateShip parts.State = parts.FieldShip
-	shipPriority := stateShip.Priority()
-	for f := range ship.Ship() {
-		if current, contains := c.currentProtectedFields[f]; !contains || current.Priority() < shipPriority {
-			c.currentProtectedFields[f] = stateShip
-		}
-	}
-	protected, err := ship.Protected()
-	if err != nil {
-		return c
-	}
-	for identifier, f := range protected {
-		c.currentProtectedFields[identifier] = f.State
-
-		if _, fullContains := c.protectedFields[identifier]; ship.Size() < SizeMax &&
-			f.State == parts.FieldProtected &&
-			!fullContains {
-			c.currentProtectedFields[identifier] = parts.FieldPotential
-		}
-	}
+	c.protectCurrent(ship)
 
 	if len(c.ships[0]) == 0 {
 		c.ships[0] = append(c.ships[0], ship)
@@ -0,0 +1,122 @@
+package setup
+
+import (
+	"context"
+	battleships "github.com/kovansky/wp-battleships"
+	"github.com/kovansky/wp-battleships/parts"
+	"github.com/kovansky/wp-battleships/tui"
+	"github.com/rs/zerolog"
+	"testing"
+)
+
+func createSetup(t *testing.T) Setup {
+	t.Helper()
+
+	ctx := context.WithValue(context.Background(), battleships.ContextKeyLog, zerolog.Nop())
+
+	theme := tui.NewTheme()
+	session := battleships.NewSession(nil, battleships.GameThemes{Player: theme, Enemy: theme, Global: theme}, battleships.DefaultSettings())
+
+	return Create(ctx, session, theme)
+}
+
+func place(t *testing.T, c Setup, anchor string, size int, orientation parts.Orientation) Setup {
+	t.Helper()
+
+	c.board.SetCursor(anchor)
+	c.shipSize = size
+	c.orientation = orientation
+
+	c = c.change(Setup.placeShip)
+	if c.errorText != "" {
+		t.Fatalf("Could not place ship at %s: %s", anchor, c.errorText)
+	}
+
+	return c
+}
+
+func TestSetup_UndoRedo(t *testing.T) {
+	c := createSetup(t)
+
+	c = place(t, c, "A10", 4, parts.Horizontal)
+	c = place(t, c, "A1", 3, parts.Horizontal)
+	afterPlacing := fingerprint(c.ships)
+
+	c = c.undo()
+	if len(c.ships[3]) != 0 || len(c.ships[4]) != 1 {
+		t.Fatalf("Undo did not remove the last ship; got: %s", fingerprint(c.ships))
+	}
+	if _, protected := c.protectedFields["B2"]; protected {
+		t.Fatalf("Protected zone of the removed ship has been kept")
+	}
+
+	c = c.redo()
+	if fingerprint(c.ships) != afterPlacing {
+		t.Fatalf("Redo did not restore the ship; expected: %s, got: %s", afterPlacing, fingerprint(c.ships))
+	}
+	if _, protected := c.protectedFields["B2"]; !protected {
+		t.Fatalf("Protected zone of the restored ship is missing")
+	}
+
+	c = c.redo()
+	if c.errorText != "nothing to redo" {
+		t.Fatalf("Expected redo to fail, got error: %q", c.errorText)
+	}
+}
+
+func TestSetup_DeleteShip(t *testing.T) {
+	c := createSetup(t)
+
+	c = place(t, c, "C5", 2, parts.Vertical)
+	c = place(t, c, "E5", 2, parts.Vertical)
+
+	c = c.change(func(c Setup) Setup {
+		return c.deleteShip("C4")
+	})
+	if len(c.ships[2]) != 1 || !c.ships[2][0].Contains("E5") {
+		t.Fatalf("Wrong ship has been deleted; got: %s", fingerprint(c.ships))
+	}
+
+	// D4 was protected by both ships, it has to stay protected after one is removed
+	if _, protected := c.protectedFields["D4"]; !protected {
+		t.Fatalf("Shared protected field has been cleared")
+	}
+	if _, protected := c.protectedFields["B4"]; protected {
+		t.Fatalf("Protected zone of the deleted ship has been kept")
+	}
+
+	c = c.change(Setup.clear)
+	if fingerprint(c.ships) != "" || len(c.protectedFields) != 0 {
+		t.Fatalf("Board has not been cleared; got: %s", fingerprint(c.ships))
+	}
+
+	c = c.undo()
+	if len(c.ships[2]) != 1 {
+		t.Fatalf("Undo did not revert clearing the board; got: %s", fingerprint(c.ships))
+	}
+}
+
+func TestSetup_Fill(t *testing.T) {
+	c := createSetup(t)
+
+	c = place(t, c, "A10", 4, parts.Vertical)
+
+	c = c.change(Setup.fill)
+	if c.errorText != "" {
+		t.Fatalf("Received unexpected error: %s", c.errorText)
+	}
+
+	for size, limit := range c.shipsLimitPerSize {
+		if len(c.ships[size]) != limit {
+			t.Fatalf("Incorrect count of ships of size %d; expected: %d, got: %d", size, limit, len(c.ships[size]))
+		}
+	}
+	if !c.ships[4][0].Contains("A10") {
+		t.Fatalf("Manually placed ship has been moved")
+	}
+
+	c = c.undo()
+	if len(c.ships[1]) != 0 || len(c.ships[4]) != 1 {
+		t.Fatalf("Undo did not revert filling; got: %s", fingerprint(c.ships))
+	}
+}
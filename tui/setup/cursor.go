@@ -0,0 +1,151 @@
+package setup
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kovansky/wp-battleships/parts"
+)
+
+var shipClassNames = map[int]string{
+	1: "one-masted",
+	2: "two-masted",
+	3: "three-masted",
+	4: "four-masted",
+}
+
+// toggleFocus switches the keyboard between cursor-driven placement on the board and the coordinates input.
+func (c Setup) toggleFocus() (Setup, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if c.board.Focused() {
+		c.board.Blur()
+		cmd = c.input.Focus()
+	} else {
+		c.board.Focus()
+		c.input.Blur()
+	}
+
+	c.refreshBoard()
+
+	return c, cmd
+}
+
+func (c Setup) updateCursor(msg tea.KeyMsg) (Setup, tea.Cmd) {
+	var cmd tea.Cmd
+
+	keys := c.session.Keys.Setup
+
+	switch {
+	case key.Matches(msg, keys.ShipClass):
+		// The keys of the binding select the ship sizes in order
+		for i, k := range keys.ShipClass.Keys() {
+			if msg.String() == k {
+				c.shipSize = i + 1
+			}
+		}
+	case key.Matches(msg, keys.Rotate):
+		c.orientation = c.orientation.Rotate()
+	case key.Matches(msg, keys.Place):
+		c = c.change(Setup.placeShip)
+	case key.Matches(msg, keys.PickUp):
+		c = c.change(Setup.pickUpShip)
+	case key.Matches(msg, keys.Fill):
+		c = c.change(Setup.fill)
+	default:
+		c.board, cmd = c.board.Update(msg)
+	}
+
+	c.refreshBoard()
+
+	return c, cmd
+}
+
+// preview returns the footprint and the protected zone of the selected ship class placed at the cursor.
+func (c Setup) preview() map[string]parts.State {
+	ship, err := parts.NewShipAt(c.board.Cursor(), c.shipSize, c.orientation)
+	if err != nil {
+		return nil
+	}
+
+	preview := map[string]parts.State{}
+
+	protected, err := ship.Protected()
+	if err != nil {
+		return nil
+	}
+	for identifier := range protected {
+		if _, contains := c.protectedFields[identifier]; !contains {
+			preview[identifier] = parts.FieldPotential
+		}
+	}
+	for identifier := range ship.Ship() {
+		preview[identifier] = parts.FieldPreview
+	}
+
+	return preview
+}
+
+// placeShip puts a whole ship of the selected class at the cursor.
+func (c Setup) placeShip() Setup {
+	c.errorText = ""
+
+	if len(c.ships[0]) > 0 {
+		c.errorText = "finish the ship you are typing in first (ok/next)"
+		return c
+	}
+
+	if shipsOfSize := c.ships[c.shipSize]; len(shipsOfSize) >= cap(shipsOfSize) {
+		c.errorText = "you have reached the limit of ships of that size"
+		return c
+	}
+
+	ship, err := parts.NewShipAt(c.board.Cursor(), c.shipSize, c.orientation)
+	if err != nil {
+		c.errorText = "the ship does not fit on the board"
+		return c
+	}
+
+	for identifier := range ship.Ship() {
+		if _, contains := c.protectedFields[identifier]; contains {
+			c.errorText = "you cannot place a ship on that field"
+			return c
+		}
+	}
+
+	c.ships[0] = append(c.ships[0], ship)
+	c = c.finishShip()
+	c.selectNextSize()
+
+	return c
+}
+
+// pickUpShip removes the ship under the cursor, so it can be moved or rotated and placed again.
+func (c Setup) pickUpShip() Setup {
+	c.errorText = ""
+
+	c, ship, found := c.removeShip(c.board.Cursor())
+	if !found {
+		c.errorText = "there is no ship on that field"
+		return c
+	}
+
+	c.shipSize = ship.Size()
+	c.orientation = ship.Orientation()
+	c.board.SetCursor(ship.Anchor())
+
+	return c
+}
+
+// selectNextSize switches the selected ship class to the biggest one which still can be placed.
+func (c *Setup) selectNextSize() {
+	if shipsOfSize := c.ships[c.shipSize]; len(shipsOfSize) < cap(shipsOfSize) {
+		return
+	}
+
+	for size := SizeMax; size > 0; size-- {
+		if shipsOfSize := c.ships[size]; len(shipsOfSize) < cap(shipsOfSize) {
+			c.shipSize = size
+			return
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package setup
+
+import (
+	"github.com/kovansky/wp-battleships/parts"
+	"math/rand"
+	"time"
+)
+
+// fill randomly places all ships which are still missing, keeping the ones already placed.
+func (c Setup) fill() Setup {
+	c.errorText = ""
+
+	if len(c.ships[0]) > 0 {
+		c.errorText = "finish the ship you are typing in first (ok/next), or delete it"
+		return c
+	}
+
+	var sizes []int
+	for size, limit := range c.shipsLimitPerSize {
+		for i := len(c.ships[size]); i < limit; i++ {
+			sizes = append(sizes, size)
+		}
+	}
+
+	if len(sizes) == 0 {
+		c.errorText = "all ships are already placed"
+		return c
+	}
+
+	ships, err := parts.FillFleet(c.protectedFields, sizes, rand.New(rand.NewSource(time.Now().UnixNano())))
+	if err != nil {
+		c.errorText = "cannot complete the fleet: " + err.Error()
+		return c
+	}
+
+	for _, ship := range ships {
+		ship, err = ship.Finish()
+		if err != nil {
+			c.errorText = "error while saving ship"
+			return c
+		}
+
+		c.ships[ship.Size()] = append(c.ships[ship.Size()], ship)
+		c.protectShip(ship)
+	}
+
+	c.selectNextSize()
+
+	return c
+}
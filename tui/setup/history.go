@@ -0,0 +1,158 @@
+package setup
+
+import (
+	"github.com/kovansky/wp-battleships/parts"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// history keeps snapshots of placed ships (including the one being built) for undo and redo.
+type history struct {
+	undo []map[int][]parts.Ship
+	redo []map[int][]parts.Ship
+}
+
+// change applies the modification and, if it changed any ship, records the previous state in history.
+func (c Setup) change(modify func(Setup) Setup) Setup {
+	before := cloneShips(c.ships)
+
+	c = modify(c)
+
+	if fingerprint(before) != fingerprint(c.ships) {
+		c.history.undo = append(c.history.undo, before)
+		c.history.redo = nil
+	}
+
+	return c
+}
+
+func (c Setup) undo() Setup {
+	c.errorText = ""
+
+	if len(c.history.undo) == 0 {
+		c.errorText = "nothing to undo"
+		return c
+	}
+
+	last := len(c.history.undo) - 1
+	c.history.redo = append(c.history.redo, cloneShips(c.ships))
+	c.ships = c.history.undo[last]
+	c.history.undo = c.history.undo[:last]
+
+	return c.restore()
+}
+
+func (c Setup) redo() Setup {
+	c.errorText = ""
+
+	if len(c.history.redo) == 0 {
+		c.errorText = "nothing to redo"
+		return c
+	}
+
+	last := len(c.history.redo) - 1
+	c.history.undo = append(c.history.undo, cloneShips(c.ships))
+	c.ships = c.history.redo[last]
+	c.history.redo = c.history.redo[:last]
+
+	return c.restore()
+}
+
+// restore recomputes the protected zones after the ships were swapped with a snapshot.
+func (c Setup) restore() Setup {
+	// Snapshots must stay untouched, in case we come back to them
+	c.ships = cloneShips(c.ships)
+	c = c.rebuildProtected()
+
+	c.currentProtectedFields = map[string]parts.State{}
+	if len(c.ships[0]) > 0 {
+		c.protectCurrent(c.ships[0][0])
+	}
+
+	c.selectNextSize()
+
+	return c
+}
+
+// clear removes all ships from the board.
+func (c Setup) clear() Setup {
+	c.errorText = ""
+
+	c.ships = emptyShips(c.shipsLimitPerSize)
+	c.protectedFields = map[string]parts.State{}
+	c.currentProtectedFields = map[string]parts.State{}
+	c.selectNextSize()
+
+	return c
+}
+
+// deleteShip removes the placed ship (or the one being built) which occupies given field.
+func (c Setup) deleteShip(field string) Setup {
+	c.errorText = ""
+
+	if len(c.ships[0]) > 0 && c.ships[0][0].Contains(field) {
+		c.ships[0] = make([]parts.Ship, 0, 1)
+		c.currentProtectedFields = map[string]parts.State{}
+		return c
+	}
+
+	c, _, found := c.removeShip(field)
+	if !found {
+		c.errorText = "there is no ship on that field"
+	}
+
+	return c
+}
+
+// removeShip takes the finished ship occupying given field off the board.
+func (c Setup) removeShip(field string) (Setup, parts.Ship, bool) {
+	for size := 1; size <= SizeMax; size++ {
+		for i, ship := range c.ships[size] {
+			if !ship.Contains(field) {
+				continue
+			}
+
+			c.ships[size] = append(c.ships[size][:i], c.ships[size][i+1:]...)
+			c = c.rebuildProtected()
+
+			return c, ship, true
+		}
+	}
+
+	return c, parts.Ship{}, false
+}
+
+// cloneShips deep copies the ships, keeping the capacities, which are used as per-size limits.
+func cloneShips(ships map[int][]parts.Ship) map[int][]parts.Ship {
+	clone := make(map[int][]parts.Ship, len(ships))
+
+	for size, shipsOfSize := range ships {
+		clone[size] = make([]parts.Ship, len(shipsOfSize), cap(shipsOfSize))
+		for i, ship := range shipsOfSize {
+			clone[size][i] = ship.Clone()
+		}
+	}
+
+	return clone
+}
+
+// fingerprint describes the ships in a way independent of the map and slice order.
+func fingerprint(ships map[int][]parts.Ship) string {
+	var descriptions []string
+
+	for size, shipsOfSize := range ships {
+		for _, ship := range shipsOfSize {
+			var fields []string
+			for f := range ship.Ship() {
+				fields = append(fields, f)
+			}
+			sort.Strings(fields)
+
+			descriptions = append(descriptions, strconv.Itoa(size)+":"+strings.Join(fields, ","))
+		}
+	}
+	sort.Strings(descriptions)
+
+	return strings.Join(descriptions, ";")
+}
@@ -1,13 +1,142 @@
 package tui
 
+import (
+	"errors"
+	"fmt"
+)
+
 type Stage string
 
 const (
 	StageLogin Stage = "login"
-	StageSetup       = "setup"
-	StageWait        = "wait"
-	StageLobby       = "lobby"
-	StageGame        = "game"
+	StageSetup Stage = "setup"
+	StageWait  Stage = "wait"
+	StageLobby Stage = "lobby"
+	StageGame  Stage = "game"
 
-	StageRanking = "ranking"
+	StageRanking Stage = "ranking"
 )
+
+// stageTransitions lists the stages reachable from each stage.
+var stageTransitions = map[Stage][]Stage{
+	StageLogin:   {StageSetup, StageLobby, StageWait},
+	StageSetup:   {StageLogin, StageLobby, StageWait},
+	StageLobby:   {StageLogin, StageGame, StageRanking},
+	StageWait:    {StageLogin, StageLobby, StageGame},
+	StageGame:    {StageLogin, StageSetup, StageWait, StageLobby, StageGame},
+	StageRanking: {StageLogin, StageLobby},
+}
+
+// stageBackTargets lists the stages each stage may go back to. The most recent of them in history is chosen.
+var stageBackTargets = map[Stage][]Stage{
+	StageSetup:   {StageLogin},
+	StageLobby:   {StageLogin},
+	StageRanking: {StageLobby, StageLogin},
+}
+
+var ErrNoWayBack = errors.New("there is no stage to go back to")
+
+type ErrIllegalTransition struct {
+	From, To Stage
+}
+
+func (e ErrIllegalTransition) Error() string {
+	return fmt.Sprintf("cannot go from %s to %s", e.From, e.To)
+}
+
+// StageHook is run when the application leaves or enters a stage.
+type StageHook func(from, to Stage)
+
+// StageMachine keeps track of the current stage, allowing only the known transitions,
+// and remembers the visited stages for going back.
+type StageMachine struct {
+	current Stage
+	history []Stage
+
+	onEnter map[Stage][]StageHook
+	onExit  map[Stage][]StageHook
+}
+
+func NewStageMachine(initial Stage) StageMachine {
+	return StageMachine{
+		current: initial,
+		onEnter: make(map[Stage][]StageHook),
+		onExit:  make(map[Stage][]StageHook),
+	}
+}
+
+func (m StageMachine) Current() Stage {
+	return m.current
+}
+
+// History returns the visited stages, the most recent last.
+func (m StageMachine) History() []Stage {
+	return append([]Stage(nil), m.history...)
+}
+
+func (m StageMachine) CanTransition(to Stage) bool {
+	for _, allowed := range stageTransitions[m.current] {
+		if allowed == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// OnEnter registers the hook run after entering the stage.
+func (m StageMachine) OnEnter(stage Stage, hook StageHook) {
+	m.onEnter[stage] = append(m.onEnter[stage], hook)
+}
+
+// OnExit registers the hook run before leaving the stage.
+func (m StageMachine) OnExit(stage Stage, hook StageHook) {
+	m.onExit[stage] = append(m.onExit[stage], hook)
+}
+
+// Transition moves to the given stage, if it is reachable from the current one.
+func (m StageMachine) Transition(to Stage) (StageMachine, error) {
+	if !m.CanTransition(to) {
+		return m, ErrIllegalTransition{From: m.current, To: to}
+	}
+
+	history := append(m.History(), m.current)
+	// Login starts everything anew, there is nothing to go back to
+	if to == StageLogin {
+		history = nil
+	}
+
+	return m.move(to, history), nil
+}
+
+// Back returns to the most recently visited stage, which the current one may go back to.
+func (m StageMachine) Back() (StageMachine, error) {
+	targets := stageBackTargets[m.current]
+
+	for i := len(m.history) - 1; i >= 0; i-- {
+		for _, target := range targets {
+			if m.history[i] == target {
+				return m.move(target, m.History()[:i]), nil
+			}
+		}
+	}
+
+	return m, ErrNoWayBack
+}
+
+func (m StageMachine) move(to Stage, history []Stage) StageMachine {
+	from := m.current
+
+	for _, hook := range m.onExit[from] {
+		hook(from, to)
+	}
+
+	m.current = to
+	m.history = history
+
+	for _, hook := range m.onEnter[to] {
+		hook(from, to)
+	}
+
+	return m
+}
@@ -0,0 +1,135 @@
+package tui_test
+
+import (
+	"errors"
+	"github.com/kovansky/wp-battleships/tui"
+	"reflect"
+	"testing"
+)
+
+func walk(t *testing.T, stages ...tui.Stage) tui.StageMachine {
+	t.Helper()
+
+	machine := tui.NewStageMachine(tui.StageLogin)
+	for _, stage := range stages {
+		var err error
+		if machine, err = machine.Transition(stage); err != nil {
+			t.Fatalf("Received unexpected error: %v", err)
+		}
+	}
+
+	return machine
+}
+
+func TestStageMachine_Transition(t *testing.T) {
+	tests := []struct {
+		name  string
+		path  []tui.Stage
+		to    tui.Stage
+		legal bool
+	}{
+		{"login to setup", nil, tui.StageSetup, true},
+		{"login to game", nil, tui.StageGame, false},
+		{"login to ranking", nil, tui.StageRanking, false},
+		{"setup to game", []tui.Stage{tui.StageSetup}, tui.StageGame, false},
+		{"wait to game", []tui.Stage{tui.StageWait}, tui.StageGame, true},
+		{"lobby to ranking", []tui.Stage{tui.StageLobby}, tui.StageRanking, true},
+		{"lobby to wait", []tui.Stage{tui.StageLobby}, tui.StageWait, false},
+		{"game to rematch", []tui.Stage{tui.StageWait, tui.StageGame}, tui.StageGame, true},
+		{"game to ranking", []tui.Stage{tui.StageWait, tui.StageGame}, tui.StageRanking, false},
+		{"ranking to game", []tui.Stage{tui.StageLobby, tui.StageRanking}, tui.StageGame, false},
+		{"login to login", nil, tui.StageLogin, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			machine := walk(t, test.path...)
+			from := machine.Current()
+
+			machine, err := machine.Transition(test.to)
+
+			if test.legal {
+				if err != nil {
+					t.Fatalf("Received unexpected error: %v", err)
+				}
+				if machine.Current() != test.to {
+					t.Fatalf("Incorrect stage; expected: %s, got: %s", test.to, machine.Current())
+				}
+				return
+			}
+
+			var illegal tui.ErrIllegalTransition
+			if !errors.As(err, &illegal) {
+				t.Fatalf("Expected illegal transition error, got: %v", err)
+			}
+			if machine.Current() != from {
+				t.Fatalf("Stage changed on illegal transition; expected: %s, got: %s", from, machine.Current())
+			}
+		})
+	}
+}
+
+func TestStageMachine_Back(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     []tui.Stage
+		expected tui.Stage
+	}{
+		{"setup to login", []tui.Stage{tui.StageSetup}, tui.StageLogin},
+		{"lobby to login", []tui.Stage{tui.StageLobby}, tui.StageLogin},
+		{"lobby through setup to login", []tui.Stage{tui.StageSetup, tui.StageLobby}, tui.StageLogin},
+		{"ranking to lobby", []tui.Stage{tui.StageLobby, tui.StageRanking}, tui.StageLobby},
+		{"ranking to lobby after a game", []tui.Stage{tui.StageLobby, tui.StageGame, tui.StageLobby, tui.StageRanking}, tui.StageLobby},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			machine, err := walk(t, test.path...).Back()
+			if err != nil {
+				t.Fatalf("Received unexpected error: %v", err)
+			}
+
+			if machine.Current() != test.expected {
+				t.Fatalf("Incorrect stage; expected: %s, got: %s", test.expected, machine.Current())
+			}
+		})
+	}
+
+	// There is no way back from the game, nor from the login
+	for _, path := range [][]tui.Stage{nil, {tui.StageWait, tui.StageGame}, {tui.StageWait}} {
+		if _, err := walk(t, path...).Back(); !errors.Is(err, tui.ErrNoWayBack) {
+			t.Fatalf("Expected no way back from %v, got: %v", path, err)
+		}
+	}
+}
+
+func TestStageMachine_Hooks(t *testing.T) {
+	var calls []string
+
+	machine := tui.NewStageMachine(tui.StageLogin)
+	machine.OnEnter(tui.StageLobby, func(from, to tui.Stage) {
+		calls = append(calls, "enter lobby from "+string(from))
+	})
+	machine.OnExit(tui.StageLobby, func(from, to tui.Stage) {
+		calls = append(calls, "exit lobby to "+string(to))
+	})
+
+	var err error
+	for _, stage := range []tui.Stage{tui.StageLobby, tui.StageRanking} {
+		if machine, err = machine.Transition(stage); err != nil {
+			t.Fatalf("Received unexpected error: %v", err)
+		}
+	}
+	if machine, err = machine.Back(); err != nil {
+		t.Fatalf("Received unexpected error: %v", err)
+	}
+
+	expected := []string{"enter lobby from login", "exit lobby to ranking", "enter lobby from ranking"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Fatalf("Incorrect hook calls; expected: %v, got: %v", expected, calls)
+	}
+
+	if history := machine.History(); !reflect.DeepEqual(history, []tui.Stage{tui.StageLogin}) {
+		t.Fatalf("Incorrect history; got: %v", history)
+	}
+}
@@ -4,10 +4,16 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	battleships "github.com/kovansky/wp-battleships"
 	"github.com/kovansky/wp-battleships/parts"
+	"github.com/mattn/go-runewidth"
+	"strings"
 )
 
+// CellWidth is the number of columns every field of a board takes. Narrower glyphs are padded to it.
+const CellWidth = 2
+
+// Brush draws a field with a glyph: a single grapheme, like X, ●, ░ or an emoji, taking up to CellWidth columns.
 type Brush struct {
-	char  byte
+	char  string
 	style lipgloss.Style
 }
 
@@ -15,11 +21,11 @@ func NewBrush() Brush {
 	return Brush{}
 }
 
-func (b Brush) Char() byte {
+func (b Brush) Char() string {
 	return b.char
 }
 
-func (b Brush) SetChar(char byte) battleships.Brush {
+func (b Brush) SetChar(char string) battleships.Brush {
 	b.char = char
 	return b
 }
@@ -44,6 +50,9 @@ type Theme struct {
 	sunk          battleships.Brush
 	miss          battleships.Brush
 	potential     battleships.Brush
+	suspected     battleships.Brush
+	ruledOut      battleships.Brush
+	annotation    battleships.Brush
 }
 
 func NewTheme() battleships.Theme {
@@ -91,7 +100,6 @@ func (t Theme) Border() battleships.Brush {
 }
 
 func (t Theme) SetBorder(brush battleships.Brush) battleships.Theme {
-	brush = brush.SetStyle(brush.Style().PaddingRight(1))
 	t.border = brush
 	return t
 }
@@ -101,7 +109,6 @@ func (t Theme) Ship() battleships.Brush {
 
 }
 func (t Theme) SetShip(brush battleships.Brush) battleships.Theme {
-	brush = brush.SetStyle(brush.Style().PaddingRight(1))
 	t.ship = brush
 	return t
 }
@@ -111,7 +118,6 @@ func (t Theme) Hit() battleships.Brush {
 
 }
 func (t Theme) SetHit(brush battleships.Brush) battleships.Theme {
-	brush = brush.SetStyle(brush.Style().PaddingRight(1))
 	t.hit = brush
 	return t
 }
@@ -121,7 +127,6 @@ func (t Theme) Sunk() battleships.Brush {
 
 }
 func (t Theme) SetSunk(brush battleships.Brush) battleships.Theme {
-	brush = brush.SetStyle(brush.Style().PaddingRight(1))
 	t.sunk = brush
 	return t
 }
@@ -130,7 +135,6 @@ func (t Theme) Miss() battleships.Brush {
 	return t.miss
 }
 func (t Theme) SetMiss(brush battleships.Brush) battleships.Theme {
-	brush = brush.SetStyle(brush.Style().PaddingRight(1))
 	t.miss = brush
 	return t
 }
@@ -139,13 +143,37 @@ func (t Theme) Potential() battleships.Brush {
 	return t.potential
 }
 func (t Theme) SetPotential(brush battleships.Brush) battleships.Theme {
-	brush = brush.SetStyle(brush.Style().PaddingRight(1))
 	t.potential = brush
 	return t
 }
 
+func (t Theme) Suspected() battleships.Brush {
+	return t.suspected
+}
+func (t Theme) SetSuspected(brush battleships.Brush) battleships.Theme {
+	t.suspected = brush
+	return t
+}
+
+func (t Theme) RuledOut() battleships.Brush {
+	return t.ruledOut
+}
+func (t Theme) SetRuledOut(brush battleships.Brush) battleships.Theme {
+	t.ruledOut = brush
+	return t
+}
+
+// Annotation is used for custom letter marks - only the style of the brush matters.
+func (t Theme) Annotation() battleships.Brush {
+	return t.annotation
+}
+func (t Theme) SetAnnotation(brush battleships.Brush) battleships.Theme {
+	t.annotation = brush
+	return t
+}
+
 func (t Theme) RenderBorder() string {
-	return t.border.Style().Render(string(t.border.Char()))
+	return t.border.Style().Render(t.border.Char())
 }
 
 func (t Theme) RenderField(state battleships.FieldState) string {
@@ -159,7 +187,7 @@ func (t Theme) RenderField(state battleships.FieldState) string {
 	case battleships.FieldStateSunk:
 		return t.RenderSunk()
 	default:
-		return "  "
+		return RenderCell("", lipgloss.NewStyle())
 	}
 }
 
@@ -173,27 +201,119 @@ func (t Theme) NewRenderField(state parts.State) string {
 		return t.RenderShip()
 	case parts.FieldPotential:
 		return t.RenderPotential()
+	case parts.FieldPreview:
+		return t.RenderPreview()
 	default:
-		return "  "
+		return RenderCell("", lipgloss.NewStyle())
 	}
 }
 
 func (t Theme) RenderShip() string {
-	return t.ship.Style().Render(string(t.ship.Char()))
+	return RenderCell(t.ship.Char(), t.ship.Style())
 }
 
 func (t Theme) RenderHit() string {
-	return t.hit.Style().Render(string(t.hit.Char()))
+	return RenderCell(t.hit.Char(), t.hit.Style())
 }
 
 func (t Theme) RenderSunk() string {
-	return t.sunk.Style().Render(string(t.sunk.Char()))
+	return RenderCell(t.sunk.Char(), t.sunk.Style())
 }
 
 func (t Theme) RenderMiss() string {
-	return t.miss.Style().Render(string(t.miss.Char()))
+	return RenderCell(t.miss.Char(), t.miss.Style())
 }
 
 func (t Theme) RenderPotential() string {
-	return t.potential.Style().Render(string(t.miss.Char()))
+	return RenderCell(t.miss.Char(), t.potential.Style())
+}
+
+func (t Theme) RenderPreview() string {
+	return RenderCell(t.ship.Char(), t.potential.Style())
+}
+
+func (t Theme) RenderAnnotation(annotation battleships.Annotation) string {
+	char, style := t.annotationGlyph(annotation)
+
+	return RenderCell(char, style)
+}
+
+func (t Theme) RenderAnnotationCursor(annotation battleships.Annotation) string {
+	char, style := t.annotationGlyph(annotation)
+
+	return RenderCursorCell(char, style)
+}
+
+func (t Theme) annotationGlyph(annotation battleships.Annotation) (string, lipgloss.Style) {
+	switch {
+	case annotation == battleships.AnnotationSuspected:
+		return t.suspected.Char(), t.suspected.Style()
+	case annotation == battleships.AnnotationRuledOut:
+		return t.ruledOut.Char(), t.ruledOut.Style()
+	case annotation.IsLetter():
+		return string(annotation), t.annotation.Style()
+	default:
+		return "", lipgloss.NewStyle()
+	}
+}
+
+// RenderCursor renders the field like RenderField does, but with the glyph highlighted.
+func (t Theme) RenderCursor(state battleships.FieldState) string {
+	var (
+		char  string
+		style = lipgloss.NewStyle()
+	)
+
+	switch state {
+	case battleships.FieldStateHit:
+		char, style = t.hit.Char(), t.hit.Style()
+	case battleships.FieldStateMiss:
+		char, style = t.miss.Char(), t.miss.Style()
+	case battleships.FieldStateShip:
+		char, style = t.ship.Char(), t.ship.Style()
+	case battleships.FieldStateSunk:
+		char, style = t.sunk.Char(), t.sunk.Style()
+	}
+
+	return RenderCursorCell(char, style)
+}
+
+// NewRenderCursor renders the field like NewRenderField does, but with the glyph highlighted.
+func (t Theme) NewRenderCursor(state parts.State) string {
+	var (
+		char  string
+		style = lipgloss.NewStyle()
+	)
+
+	switch state {
+	case parts.FieldMiss, parts.FieldProtected, parts.FieldCorner:
+		char, style = t.miss.Char(), t.miss.Style()
+	case parts.FieldHit:
+		char, style = t.hit.Char(), t.hit.Style()
+	case parts.FieldShip:
+		char, style = t.ship.Char(), t.ship.Style()
+	case parts.FieldPotential:
+		char, style = t.miss.Char(), t.potential.Style()
+	case parts.FieldPreview:
+		char, style = t.ship.Char(), t.potential.Style()
+	}
+
+	return RenderCursorCell(char, style)
+}
+
+// RenderCell draws the glyph padded to the width of a field. Glyphs wider than a field are cut.
+func RenderCell(glyph string, style lipgloss.Style) string {
+	glyph = runewidth.Truncate(glyph, CellWidth, "")
+
+	return style.Copy().PaddingRight(CellWidth - runewidth.StringWidth(glyph)).Render(glyph)
+}
+
+// RenderCursorCell draws the glyph like RenderCell does, but highlighted. An empty field highlights a space.
+func RenderCursorCell(glyph string, style lipgloss.Style) string {
+	glyph = runewidth.Truncate(glyph, CellWidth, "")
+	if glyph == "" {
+		glyph = " "
+	}
+
+	return style.Copy().Reverse(true).Render(glyph) + strings.Repeat(" ", CellWidth-runewidth.StringWidth(glyph))
 }
@@ -0,0 +1,223 @@
+package themes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/BurntSushi/toml"
+	"github.com/charmbracelet/lipgloss"
+	battleships "github.com/kovansky/wp-battleships"
+	"github.com/kovansky/wp-battleships/tui"
+	"github.com/mattn/go-runewidth"
+	"github.com/rivo/uniseg"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Style describes a lipgloss style. Colours are hex codes (#ff0000) or ANSI colour numbers (0-255).
+type Style struct {
+	Foreground string `json:"foreground" toml:"foreground"`
+	Background string `json:"background" toml:"background"`
+	Bold       bool   `json:"bold" toml:"bold"`
+	Italic     bool   `json:"italic" toml:"italic"`
+	Underline  bool   `json:"underline" toml:"underline"`
+	Faint      bool   `json:"faint" toml:"faint"`
+}
+
+type Brush struct {
+	Char string `json:"char" toml:"char"`
+	Style
+}
+
+// Theme describes every style and brush of a battleships.Theme.
+type Theme struct {
+	Rows          Style `json:"rows" toml:"rows"`
+	Cols          Style `json:"cols" toml:"cols"`
+	TextPrimary   Style `json:"text_primary" toml:"text_primary"`
+	TextSecondary Style `json:"text_secondary" toml:"text_secondary"`
+
+	Border     Brush `json:"border" toml:"border"`
+	Ship       Brush `json:"ship" toml:"ship"`
+	Hit        Brush `json:"hit" toml:"hit"`
+	Sunk       Brush `json:"sunk" toml:"sunk"`
+	Miss       Brush `json:"miss" toml:"miss"`
+	Potential  Brush `json:"potential" toml:"potential"`
+	Suspected  Brush `json:"suspected" toml:"suspected"`
+	RuledOut   Brush `json:"ruled_out" toml:"ruled_out"`
+	Annotation Brush `json:"annotation" toml:"annotation"`
+}
+
+// File is the content of a theme file. The enemy's board looks like the player's one, unless it is set.
+type File struct {
+	Name   string `json:"name" toml:"name"`
+	Player Theme  `json:"player" toml:"player"`
+	Enemy  *Theme `json:"enemy" toml:"enemy"`
+	Global Theme  `json:"global" toml:"global"`
+}
+
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// Load reads the theme file, TOML or JSON depending on the extension.
+func Load(path string) (battleships.GameThemes, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return battleships.GameThemes{}, err
+	}
+
+	return parse(content, filepath.Ext(path))
+}
+
+func parse(content []byte, extension string) (battleships.GameThemes, error) {
+	var (
+		file File
+		err  error
+	)
+
+	switch strings.ToLower(extension) {
+	case ".toml":
+		err = toml.Unmarshal(content, &file)
+	case ".json":
+		err = json.Unmarshal(content, &file)
+	default:
+		return battleships.GameThemes{}, fmt.Errorf("unknown theme format %q, use .toml or .json", extension)
+	}
+	if err != nil {
+		return battleships.GameThemes{}, err
+	}
+
+	return file.GameThemes()
+}
+
+func (f File) GameThemes() (battleships.GameThemes, error) {
+	var (
+		themes battleships.GameThemes
+		err    error
+	)
+
+	if themes.Player, err = f.Player.Theme(); err != nil {
+		return themes, fmt.Errorf("player: %w", err)
+	}
+
+	themes.Enemy = themes.Player
+	if f.Enemy != nil {
+		if themes.Enemy, err = f.Enemy.Theme(); err != nil {
+			return themes, fmt.Errorf("enemy: %w", err)
+		}
+	}
+
+	if themes.Global, err = f.Global.Theme(); err != nil {
+		return themes, fmt.Errorf("global: %w", err)
+	}
+
+	return themes, nil
+}
+
+func (t Theme) Theme() (battleships.Theme, error) {
+	theme := tui.NewTheme()
+
+	styles := []struct {
+		name  string
+		style Style
+		set   func(battleships.Theme, lipgloss.Style) battleships.Theme
+	}{
+		{"rows", t.Rows, battleships.Theme.SetRows},
+		{"cols", t.Cols, battleships.Theme.SetCols},
+		{"text_primary", t.TextPrimary, battleships.Theme.SetTextPrimary},
+		{"text_secondary", t.TextSecondary, battleships.Theme.SetTextSecondary},
+	}
+	for _, s := range styles {
+		style, err := s.style.lipgloss()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", s.name, err)
+		}
+		theme = s.set(theme, style)
+	}
+
+	brushes := []struct {
+		name  string
+		brush Brush
+		set   func(battleships.Theme, battleships.Brush) battleships.Theme
+	}{
+		{"border", t.Border, battleships.Theme.SetBorder},
+		{"ship", t.Ship, battleships.Theme.SetShip},
+		{"hit", t.Hit, battleships.Theme.SetHit},
+		{"sunk", t.Sunk, battleships.Theme.SetSunk},
+		{"miss", t.Miss, battleships.Theme.SetMiss},
+		{"potential", t.Potential, battleships.Theme.SetPotential},
+		{"suspected", t.Suspected, battleships.Theme.SetSuspected},
+		{"ruled_out", t.RuledOut, battleships.Theme.SetRuledOut},
+		{"annotation", t.Annotation, battleships.Theme.SetAnnotation},
+	}
+	for _, b := range brushes {
+		brush, err := b.brush.brush()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", b.name, err)
+		}
+		theme = b.set(theme, brush)
+	}
+
+	return theme, nil
+}
+
+func (s Style) lipgloss() (lipgloss.Style, error) {
+	style := lipgloss.NewStyle().
+		Bold(s.Bold).
+		Italic(s.Italic).
+		Underline(s.Underline).
+		Faint(s.Faint)
+
+	if s.Foreground != "" {
+		color, err := parseColor(s.Foreground)
+		if err != nil {
+			return style, err
+		}
+		style = style.Foreground(color)
+	}
+
+	if s.Background != "" {
+		color, err := parseColor(s.Background)
+		if err != nil {
+			return style, err
+		}
+		style = style.Background(color)
+	}
+
+	return style, nil
+}
+
+// brush makes the brush. Brushes without a glyph take it from elsewhere, like the letter of a custom mark.
+func (b Brush) brush() (battleships.Brush, error) {
+	style, err := b.Style.lipgloss()
+	if err != nil {
+		return nil, err
+	}
+
+	brush := tui.NewBrush().SetStyle(style)
+	if b.Char == "" {
+		return brush, nil
+	}
+
+	if uniseg.GraphemeClusterCount(b.Char) != 1 {
+		return nil, fmt.Errorf("glyph %q must be a single character", b.Char)
+	}
+	if width := runewidth.StringWidth(b.Char); width < 1 || width > tui.CellWidth {
+		return nil, fmt.Errorf("glyph %q takes %d columns, it may take 1 to %d", b.Char, width, tui.CellWidth)
+	}
+
+	return brush.SetChar(b.Char), nil
+}
+
+func parseColor(value string) (lipgloss.Color, error) {
+	if hexColor.MatchString(value) {
+		return lipgloss.Color(value), nil
+	}
+
+	if n, err := strconv.Atoi(value); err == nil && n >= 0 && n <= 255 {
+		return lipgloss.Color(value), nil
+	}
+
+	return "", errors.New("invalid colour " + strconv.Quote(value) + ", use a hex code or an ANSI colour number")
+}
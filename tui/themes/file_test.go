@@ -0,0 +1,73 @@
+package themes_test
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/kovansky/wp-battleships/tui/themes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPreset(t *testing.T) {
+	for _, name := range themes.Presets {
+		t.Run(name, func(t *testing.T) {
+			gameThemes, err := themes.Preset(name)
+			if err != nil {
+				t.Fatalf("Received unexpected error: %v", err)
+			}
+
+			for _, theme := range []interface{ RenderHit() string }{gameThemes.Player, gameThemes.Enemy} {
+				if theme.RenderHit() == "" {
+					t.Fatalf("Hit glyph has not been set")
+				}
+			}
+		})
+	}
+
+	if _, err := themes.Preset("missing"); err == nil {
+		t.Fatalf("Expected an error about the unknown preset")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		valid   bool
+	}{
+		{"toml", "theme.toml", "[player]\nhit = { char = \"*\", foreground = \"#ff0000\" }\n[enemy]\nhit = { char = \"+\" }", true},
+		{"json", "theme.json", `{"player": {"hit": {"char": "*", "foreground": "196"}}, "enemy": {"hit": {"char": "+"}}}`, true},
+		{"bad colour", "theme.toml", "[player]\nhit = { char = \"*\", foreground = \"red\" }", false},
+		{"two glyphs", "theme.toml", "[player]\nhit = { char = \"**\" }", false},
+		{"zero width glyph", "theme.toml", "[player]\nhit = { char = \"\u200B\" }", false},
+		{"format", "theme.yaml", "player: {}", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), test.file)
+			if err := os.WriteFile(path, []byte(test.content), 0o600); err != nil {
+				t.Fatalf("Received unexpected error: %v", err)
+			}
+
+			gameThemes, err := themes.Load(path)
+			if !test.valid {
+				if err == nil {
+					t.Fatalf("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Received unexpected error: %v", err)
+			}
+
+			if gameThemes.Player.Hit().Char() != "*" || gameThemes.Enemy.Hit().Char() != "+" {
+				t.Fatalf("Player and enemy should look different, got: %q and %q", gameThemes.Player.Hit().Char(), gameThemes.Enemy.Hit().Char())
+			}
+			if _, unset := gameThemes.Player.Hit().Style().GetForeground().(lipgloss.NoColor); unset {
+				t.Fatalf("Colour of the hit has not been set")
+			}
+		})
+	}
+}
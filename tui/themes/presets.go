@@ -0,0 +1,45 @@
+package themes
+
+import (
+	"embed"
+	"fmt"
+	battleships "github.com/kovansky/wp-battleships"
+)
+
+// Default is the preset used when the player hasn't chosen a theme.
+const Default = "classic"
+
+// Presets are the names of the built-in themes, in the order the theme switcher goes through them.
+var Presets = []string{"classic", "high-contrast", "solarized", "monochrome", "deuteranopia", "protanopia", "tritanopia"}
+
+//go:embed presets/*.toml
+var presets embed.FS
+
+func Preset(name string) (battleships.GameThemes, error) {
+	content, err := presets.ReadFile("presets/" + name + ".toml")
+	if err != nil {
+		return battleships.GameThemes{}, fmt.Errorf("unknown theme %q", name)
+	}
+
+	return parse(content, ".toml")
+}
+
+// IsPreset tells if the theme is a built-in one, rather than a path to a theme file.
+func IsPreset(theme string) bool {
+	for _, preset := range Presets {
+		if preset == theme {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Resolve returns the preset with the given name, or loads the theme file from the given path.
+func Resolve(theme string) (battleships.GameThemes, error) {
+	if IsPreset(theme) {
+		return Preset(theme)
+	}
+
+	return Load(theme)
+}
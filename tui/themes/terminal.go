@@ -0,0 +1,12 @@
+package themes
+
+import "github.com/muesli/termenv"
+
+// Fallback is the theme for terminals which can't show the colours of the other themes.
+const Fallback = "monochrome"
+
+// LowColor tells whether the terminal shows too few colours to tell the fields apart by them,
+// or the player has turned the colours off with NO_COLOR.
+func LowColor(output *termenv.Output) bool {
+	return output.EnvColorProfile() > termenv.ANSI256
+}
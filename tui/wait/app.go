@@ -30,7 +30,7 @@ func Create(ctx context.Context, theme battleships.Theme) Wait {
 	header := common.CreateHeader("Battleships", theme, asciiRender)
 	spinnerComponent := CreateSpinner()
 	spinnerComponent.Spinner = spinner.Points
-	spinnerComponent.Style = battleships.Themes.Global.TextSecondary()
+	spinnerComponent.Style = theme.TextSecondary()
 
 	return Wait{
 		ctx:   ctx,
@@ -55,15 +55,16 @@ func (c Wait) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	)
 
 	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c":
-			return c, tea.Quit
-		}
 	case spinner.TickMsg:
 		var cmd tea.Cmd
 		c.subcomponents["spinner"], cmd = c.subcomponents["spinner"].(Spinner).Update(msg)
 		return c, cmd
+	case battleships.ThemesChangeMsg:
+		c.theme = msg.Themes.Global
+
+		spinnerComponent := c.subcomponents["spinner"].(Spinner)
+		spinnerComponent.Style = c.theme.TextSecondary()
+		c.subcomponents["spinner"] = spinnerComponent
 	}
 
 	for name, cmp := range c.subcomponents {
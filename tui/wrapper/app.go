@@ -2,12 +2,14 @@ package wrapper
 
 import (
 	"context"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	battleships "github.com/kovansky/wp-battleships"
-	"github.com/kovansky/wp-battleships/routines"
-	"github.com/kovansky/wp-battleships/ships"
 	"github.com/kovansky/wp-battleships/tui"
 	"github.com/kovansky/wp-battleships/tui/board"
+	"github.com/kovansky/wp-battleships/tui/common"
 	"github.com/kovansky/wp-battleships/tui/lobby"
 	"github.com/kovansky/wp-battleships/tui/login"
 	"github.com/kovansky/wp-battleships/tui/ranking"
@@ -15,15 +17,15 @@ import (
 	"github.com/kovansky/wp-battleships/tui/wait"
 	"github.com/mbndr/figlet4go"
 	"github.com/rs/zerolog"
-	"time"
 )
 
 type Application struct {
-	ctx context.Context
-	log zerolog.Logger
+	ctx     context.Context
+	log     zerolog.Logger
+	session *battleships.Session
 
-	stage tui.Stage
-	theme battleships.Theme
+	stages tui.StageMachine
+	theme  battleships.Theme
 
 	login   login.Login
 	lobby   lobby.Lobby
@@ -34,209 +36,174 @@ type Application struct {
 
 	width, height int
 
+	// err is the persistent error the player has to decide about
+	err *tui.ErrorMsg
+
+	// toasts are the notifications, showNotifications shows the ones shown so far
+	toasts            common.Toasts
+	showNotifications bool
+
+	// debug shows the state of the background routines
+	debug bool
+
+	// themeChoices are the themes the player switches between
+	themeChoices []string
+
+	// showHelp shows the key bindings of the current stage
+	showHelp bool
+	help     help.Model
+
 	asciiRender *figlet4go.AsciiRender
 }
 
-func Create(ctx context.Context, theme battleships.Theme) Application {
+func Create(ctx context.Context, session *battleships.Session, theme battleships.Theme) Application {
 	asciiRender := figlet4go.NewAsciiRender()
 
 	log := ctx.Value(battleships.ContextKeyLog).(zerolog.Logger)
 
-	loginApp := login.Create(ctx, theme)
+	loginApp := login.Create(ctx, session, theme)
+
+	stages := tui.NewStageMachine(tui.StageLogin)
+	superviseRoutines(ctx, session, stages)
 
 	return Application{
 		ctx:         ctx,
+		session:     session,
 		log:         log,
 		theme:       theme,
-		stage:       tui.StageLogin,
+		stages:      stages,
 		login:       loginApp,
+		help:        help.New(),
+		toasts:      common.CreateToasts(theme),
 		asciiRender: asciiRender,
+
+		themeChoices: themeChoices(session.Settings.Theme),
 	}
 }
 
 func (c Application) Init() tea.Cmd {
 	var cmds []tea.Cmd
 
-	cmds = append(cmds, c.login.Init())
+	cmds = append(cmds, c.login.Init(), c.keyConflicts())
 
 	return tea.Batch(cmds...)
 }
 
 func (c Application) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	var (
-		tmp  tea.Model
-		cmd  tea.Cmd
-		cmds []tea.Cmd
-	)
+	var cmd tea.Cmd
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c":
-			if battleships.Routines.Game != nil {
-				battleships.Routines.Game.Quit()
-			}
-			if battleships.Routines.Lobby != nil {
-				battleships.Routines.Lobby.Quit()
-			}
-
-			return c, tea.Quit
+		keys := c.session.Keys.Global
+
+		switch {
+		case key.Matches(msg, keys.Quit):
+			return c, c.quit()
+		case key.Matches(msg, keys.Debug):
+			c.debug = !c.debug
+			return c, nil
+		case key.Matches(msg, keys.Theme):
+			c, cmd = c.switchTheme()
+			return c, cmd
 		}
-		break
-	case tui.ApplicationStageChangeMsg:
-		switch msg.Stage {
-		case tui.StageLogin:
-			c.stage = msg.Stage
-
-			if msg.From == tui.StageGame {
-				battleships.Routines.Game.Quit()
-			}
-		case tui.StageRanking:
-			c.stage = msg.Stage
-
-			players, _ := battleships.ServerClient.Stats()
 
-			if len(battleships.PlayerData.Nick) > 0 {
-				player, err := battleships.ServerClient.PlayerStats(battleships.PlayerData.Nick)
-				if err == nil {
-					players = append(players, ships.NewPlayerFromStats(player))
-				}
-			}
+		var handled bool
+		if c, handled = c.updateNotifications(msg); handled {
+			return c, nil
+		}
 
-			c.ranking = ranking.Create(c.ctx, c.theme, players)
-			cmds = append(cmds, c.ranking.Init())
-
-			tmp, cmd = c.ranking.Update(tea.WindowSizeMsg{
-				Width:  c.width,
-				Height: c.height,
-			})
-			c.ranking = tmp.(ranking.Ranking)
-			cmds = append(cmds, cmd)
-			break
-		case tui.StageLobby:
-			c.stage = msg.Stage
-			if msg.Model != nil {
-				c.lobby = msg.Model.(lobby.Lobby)
-			}
+		if c, handled = c.updateHelp(msg); handled {
+			return c, nil
+		}
 
-			cmds = append(cmds, c.lobby.Init())
+		if c, cmd, handled = c.updateErrorDialog(msg); handled {
+			return c, cmd
+		}
 
-			tmp, cmd = c.lobby.Update(tea.WindowSizeMsg{
-				Width:  c.width,
-				Height: c.height,
-			})
-			c.lobby = tmp.(lobby.Lobby)
-			cmds = append(cmds, cmd)
+		if key.Matches(msg, keys.Help) {
+			c.showHelp = true
+			return c, nil
+		}
 
-			battleships.Routines.Lobby = routines.CreateLobby(c.ctx, 3*time.Second, make(chan struct{}))
-			go battleships.Routines.Lobby.Run()
+		if key.Matches(msg, keys.Notifications) {
+			c.showNotifications = true
+			return c, nil
+		}
 
-			if msg.From == tui.StageGame {
-				battleships.Routines.Game.Quit()
-			}
-			if msg.From == tui.StageWait {
-				battleships.Routines.Wait.Quit()
-			}
-			break
-		case tui.StageSetup:
-			c.setup = msg.Model.(setup.Setup)
-			c.stage = msg.Stage
-
-			cmds = append(cmds, c.setup.Init())
-
-			tmp, cmd = c.setup.Update(tea.WindowSizeMsg{
-				Width:  c.width,
-				Height: c.height,
-			})
-			c.setup = tmp.(setup.Setup)
-			cmds = append(cmds, cmd)
-			break
-		case tui.StageWait:
-			c.wait = msg.Model.(wait.Wait)
-			c.stage = msg.Stage
-
-			cmds = append(cmds, c.wait.Init())
-
-			tmp, cmd = c.wait.Update(tea.WindowSizeMsg{
-				Width:  c.width,
-				Height: c.height,
-			})
-			c.wait = tmp.(wait.Wait)
-			cmds = append(cmds, cmd)
-
-			battleships.Routines.Wait = routines.CreateWait(c.ctx, 1*time.Second, 7*time.Second, make(chan struct{}))
-			go battleships.Routines.Wait.Run()
-
-			break
-		case tui.StageGame:
-			c.game = msg.Model.(board.Full)
-			c.stage = msg.Stage
-
-			cmds = append(cmds, c.game.Init())
-
-			tmp, cmd = c.game.Update(tea.WindowSizeMsg{
-				Width:  c.width,
-				Height: c.height,
-			})
-			c.game = tmp.(board.Full)
-			cmds = append(cmds, cmd)
-
-			battleships.Routines.Game = routines.CreateGame(c.ctx, 1*time.Second, c.theme, make(chan struct{}))
-			go battleships.Routines.Game.Run()
-
-			switch msg.From {
-			case tui.StageLobby:
-				battleships.Routines.Lobby.Quit()
-			case tui.StageWait:
-				battleships.Routines.Wait.Quit()
+		if key.Matches(msg, keys.Back) {
+			if c, cmd, handled = c.back(); handled {
+				return c, cmd
 			}
-			break
 		}
 		break
+	case tui.ErrorMsg:
+		c, cmd = c.showError(msg)
+		return c, cmd
+	case tui.NotifyMsg, common.ToastExpiredMsg:
+		c, cmd = c.updateToasts(msg)
+		return c, cmd
+	case tui.ApplicationStageChangeMsg:
+		c, cmd = c.changeStage(msg)
+		return c, cmd
+	case battleships.ThemesChangeMsg:
+		c, cmd = c.reloadThemes(msg)
+		return c, cmd
+	case tui.NextGameMsg:
+		c, cmd = c.nextGame(msg)
+		return c, cmd
 	case tea.WindowSizeMsg:
 		c.width = msg.Width
 		c.height = msg.Height
 		break
 	}
 
-	switch c.stage {
+	return c.updateStage(msg)
+}
+
+// updateStage passes the message to the model of the current stage.
+func (c Application) updateStage(msg tea.Msg) (Application, tea.Cmd) {
+	var (
+		tmp tea.Model
+		cmd tea.Cmd
+	)
+
+	switch c.stages.Current() {
 	case tui.StageLogin:
 		tmp, cmd = c.login.Update(msg)
 		c.login = tmp.(login.Login)
-		cmds = append(cmds, cmd)
-		break
 	case tui.StageSetup:
 		tmp, cmd = c.setup.Update(msg)
 		c.setup = tmp.(setup.Setup)
-		cmds = append(cmds, cmd)
-		break
 	case tui.StageWait:
 		tmp, cmd = c.wait.Update(msg)
 		c.wait = tmp.(wait.Wait)
-		cmds = append(cmds, cmd)
-		break
 	case tui.StageLobby:
 		tmp, cmd = c.lobby.Update(msg)
 		c.lobby = tmp.(lobby.Lobby)
-		cmds = append(cmds, cmd)
-		break
 	case tui.StageGame:
 		tmp, cmd = c.game.Update(msg)
 		c.game = tmp.(board.Full)
-		cmds = append(cmds, cmd)
-		break
 	case tui.StageRanking:
 		tmp, cmd = c.ranking.Update(msg)
 		c.ranking = tmp.(ranking.Ranking)
-		cmds = append(cmds, cmd)
-		break
 	}
 
-	return c, tea.Batch(cmds...)
+	return c, cmd
 }
 
 func (c Application) View() string {
-	switch c.stage {
+	var overlays []string
+	for _, overlay := range []string{c.errorView(), c.toasts.View(), c.helpView(), c.notificationsView(), c.debugView()} {
+		if overlay != "" {
+			overlays = append(overlays, overlay)
+		}
+	}
+
+	return overlayTop(c.stageView(), lipgloss.JoinVertical(lipgloss.Left, overlays...))
+}
+
+func (c Application) stageView() string {
+	switch c.stages.Current() {
 	case tui.StageLogin:
 		return c.login.View()
 	case tui.StageSetup:
@@ -253,3 +220,8 @@ func (c Application) View() string {
 
 	return ""
 }
+
+// quit exits the program. The session is closed once the program has stopped, wherever it is quit from.
+func (c Application) quit() tea.Cmd {
+	return tea.Quit
+}
@@ -0,0 +1,103 @@
+package wrapper
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/kovansky/wp-battleships/tui"
+	"github.com/kovansky/wp-battleships/tui/lobby"
+	"strings"
+)
+
+// showError displays the error. Transient errors are notifications, persistent ones wait for the player.
+func (c Application) showError(msg tui.ErrorMsg) (Application, tea.Cmd) {
+	if !msg.Persistent {
+		return c.updateToasts(tui.NotifyMsg{Severity: tui.SeverityError, Message: msg.Error()})
+	}
+
+	c.err = &msg
+
+	return c, nil
+}
+
+// updateErrorDialog handles the choice after a persistent error. Returns false if the key was not handled.
+func (c Application) updateErrorDialog(msg tea.KeyMsg) (Application, tea.Cmd, bool) {
+	if c.err == nil {
+		return c, nil, false
+	}
+
+	keys := c.session.Keys.Error
+
+	switch {
+	case key.Matches(msg, keys.Retry):
+		retry := c.err.Retry
+		c.err = nil
+
+		return c, retry, true
+	case key.Matches(msg, keys.Lobby):
+		c.err = nil
+
+		// We are in the lobby already, dismissing the error is enough
+		if c.stages.Current() == tui.StageLobby {
+			return c, nil, true
+		}
+
+		return c, func() tea.Msg {
+			return tui.ApplicationStageChangeMsg{
+				From:  c.stages.Current(),
+				Stage: tui.StageLobby,
+				Model: lobby.Create(c.ctx, c.session, c.session.Themes().Global, nil),
+			}
+		}, true
+	case key.Matches(msg, keys.Quit):
+		return c, c.quit(), true
+	case key.Matches(msg, keys.Dismiss):
+		c.err = nil
+
+		return c, nil, true
+	}
+
+	// Don't let the keys through to the stage while the player has to decide
+	return c, nil, true
+}
+
+func (c Application) errorView() string {
+	if c.err == nil {
+		return ""
+	}
+
+	message := c.theme.TextSecondary().Copy().Bold(true).Render(c.err.Error())
+
+	keys := c.session.Keys.Error
+
+	options := tui.KeyOptions(keys.Retry, keys.Lobby, keys.Quit, keys.Dismiss)
+	if c.err.Retry == nil {
+		options = tui.KeyOptions(keys.Lobby, keys.Quit, keys.Dismiss)
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.ThickBorder()).
+		BorderForeground(c.theme.TextSecondary().GetForeground()).
+		Padding(0, 1).
+		Render(lipgloss.JoinVertical(lipgloss.Left, message, "", options))
+}
+
+// overlayTop draws the overlay over the first lines of the view, so the layout of the view does not move.
+func overlayTop(view, overlay string) string {
+	if overlay == "" {
+		return view
+	}
+
+	viewLines := strings.Split(view, "\n")
+	overlayLines := strings.Split(overlay, "\n")
+
+	for i, line := range overlayLines {
+		if i < len(viewLines) {
+			viewLines[i] = line
+		} else {
+			viewLines = append(viewLines, line)
+		}
+	}
+
+	return strings.Join(viewLines, "\n")
+}
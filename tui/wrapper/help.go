@@ -0,0 +1,51 @@
+package wrapper
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/kovansky/wp-battleships/tui"
+	"strings"
+)
+
+// updateHelp closes the help overlay. Returns false if the overlay is not shown.
+func (c Application) updateHelp(msg tea.KeyMsg) (Application, bool) {
+	if !c.showHelp {
+		return c, false
+	}
+
+	if key.Matches(msg, c.session.Keys.Global.Help, c.session.Keys.Global.Back) {
+		c.showHelp = false
+	}
+
+	// Keys meant for the stage would act on a screen the player can't see
+	return c, true
+}
+
+func (c Application) helpView() string {
+	if !c.showHelp {
+		return ""
+	}
+
+	title := c.theme.TextPrimary().Copy().Bold(true).Render("Keys - " + string(c.stages.Current()))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder()).
+		Padding(0, 1).
+		Render(lipgloss.JoinVertical(lipgloss.Left, title, "", c.help.FullHelpView(tui.StageHelp(c.session.Keys, c.stages.Current()))))
+}
+
+// keyConflicts reports the keys bound to more than one action, so the player knows why some of them don't work.
+func (c Application) keyConflicts() tea.Cmd {
+	conflicts := c.session.Keys.Conflicts()
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	descriptions := make([]string, len(conflicts))
+	for i, conflict := range conflicts {
+		descriptions[i] = conflict.String()
+	}
+
+	return tui.Notify(tui.SeverityWarning, "Conflicting key bindings: "+strings.Join(descriptions, "; "))
+}
@@ -0,0 +1,95 @@
+package wrapper
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	battleships "github.com/kovansky/wp-battleships"
+	"github.com/kovansky/wp-battleships/tui"
+	"github.com/kovansky/wp-battleships/tui/board"
+	"github.com/kovansky/wp-battleships/tui/lobby"
+	"github.com/kovansky/wp-battleships/tui/setup"
+	"github.com/kovansky/wp-battleships/tui/wait"
+)
+
+// nextGame prepares what the player has chosen after the game has ended. The nick, description and board
+// stay the same, so the player doesn't have to type them in again.
+func (c Application) nextGame(msg tui.NextGameMsg) (Application, tea.Cmd) {
+	// Keep the board the server has drawn for us, so the next game is played with the same one
+	if player := c.session.Player(); len(player.Board) == 0 && c.session.Game() != nil {
+		for field, state := range c.session.Game().Board() {
+			if state != battleships.FieldStateMiss {
+				player.Board = append(player.Board, field)
+			}
+		}
+		c.session.SetPlayer(player)
+	}
+
+	var cmd tea.Cmd
+
+	cmd = func() tea.Msg {
+		var (
+			app   tea.Model
+			stage tui.Stage
+		)
+
+		switch msg.Choice {
+		case tui.NextGameSetup:
+			stage = tui.StageSetup
+			app = setup.Create(c.ctx, c.session, c.theme)
+		case tui.NextGameLobby:
+			var players []battleships.Player
+
+			err := battleships.Retry(tui.RetryAttempts, tui.RetryDelay, func() (err error) {
+				players, err = c.session.Client.ListPlayers()
+				return err
+			})
+			if err != nil {
+				return tui.ErrorMsg{Message: "Failed to list players", Err: err, Persistent: true, Retry: cmd}
+			}
+
+			stage = tui.StageLobby
+			app = lobby.Create(c.ctx, c.session, c.session.Themes().Global, players)
+		case tui.NextGameRematch:
+			game, errMsg := tui.InitGame(c.session, msg.Opponent, cmd)
+			if errMsg != nil {
+				return *errMsg
+			}
+
+			err := battleships.Retry(tui.RetryAttempts, tui.RetryDelay, func() error {
+				return c.session.Client.UpdateBoard(game)
+			})
+			if err != nil {
+				return tui.ErrorMsg{Message: "Couldn't update the game board", Err: err, Persistent: true, Retry: cmd}
+			}
+			err = battleships.Retry(tui.RetryAttempts, tui.RetryDelay, func() error {
+				return c.session.Client.GameStatus(game)
+			})
+			if err != nil {
+				return tui.ErrorMsg{Message: "Couldn't update the game status", Err: err, Persistent: true, Retry: cmd}
+			}
+
+			c.session.SetGame(game)
+
+			stage = tui.StageGame
+			app = board.InitFull(c.session, lipgloss.NewStyle().Italic(true).Render("Waiting for game..."))
+		default:
+			game, errMsg := tui.InitGame(c.session, "", cmd)
+			if errMsg != nil {
+				return *errMsg
+			}
+
+			c.session.SetGame(game)
+
+			stage = tui.StageWait
+			app = wait.Create(c.ctx, c.theme)
+		}
+
+		return tui.ApplicationStageChangeMsg{
+			From:  tui.StageGame,
+			Stage: stage,
+			Model: app,
+		}
+	}
+
+	return c, cmd
+}
@@ -0,0 +1,40 @@
+package wrapper
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/kovansky/wp-battleships/tui/common"
+)
+
+// updateToasts passes the notification, or its expiry, to the toasts.
+func (c Application) updateToasts(msg tea.Msg) (Application, tea.Cmd) {
+	tmp, cmd := c.toasts.Update(msg)
+	c.toasts = tmp.(common.Toasts)
+
+	return c, cmd
+}
+
+// updateNotifications closes the notifications history. Returns false if it is not shown.
+func (c Application) updateNotifications(msg tea.KeyMsg) (Application, bool) {
+	if !c.showNotifications {
+		return c, false
+	}
+
+	if key.Matches(msg, c.session.Keys.Global.Notifications, c.session.Keys.Global.Back) {
+		c.showNotifications = false
+	}
+
+	return c, true
+}
+
+func (c Application) notificationsView() string {
+	if !c.showNotifications {
+		return ""
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder()).
+		Padding(0, 1).
+		Render(c.toasts.HistoryView())
+}
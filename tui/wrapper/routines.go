@@ -0,0 +1,78 @@
+package wrapper
+
+import (
+	"context"
+	"fmt"
+	"github.com/charmbracelet/lipgloss"
+	battleships "github.com/kovansky/wp-battleships"
+	"github.com/kovansky/wp-battleships/routines"
+	"github.com/kovansky/wp-battleships/tui"
+	"strings"
+	"time"
+)
+
+// stageRoutines is the background routine each stage needs, running only while the stage is active.
+var stageRoutines = map[tui.Stage]battleships.RoutineKind{
+	tui.StageLobby: battleships.RoutineLobby,
+	tui.StageWait:  battleships.RoutineWait,
+	tui.StageGame:  battleships.RoutineGame,
+}
+
+// superviseRoutines starts the routine each stage needs when the stage is entered, and stops it on exit.
+func superviseRoutines(ctx context.Context, session *battleships.Session, stages tui.StageMachine) {
+	for stage, kind := range stageRoutines {
+		kind := kind
+
+		stages.OnEnter(stage, func(_, _ tui.Stage) {
+			switch kind {
+			case battleships.RoutineLobby:
+				session.Routines.Start(routines.CreateLobby(ctx, session, 3*time.Second))
+			case battleships.RoutineWait:
+				session.Routines.Start(routines.CreateWait(ctx, session, 1*time.Second, 7*time.Second))
+			case battleships.RoutineGame:
+				session.Routines.Start(routines.CreateGame(ctx, session, 1*time.Second))
+			}
+		})
+		stages.OnExit(stage, func(_, _ tui.Stage) {
+			session.Routines.Stop(kind)
+		})
+	}
+}
+
+// debugView shows the state of the background routines.
+func (c Application) debugView() string {
+	if !c.debug {
+		return ""
+	}
+
+	lines := []string{c.theme.TextPrimary().Copy().Bold(true).Render("Routines")}
+
+	statuses := c.session.Routines.Status()
+	if len(statuses) == 0 {
+		lines = append(lines, "none started")
+	}
+
+	for _, status := range statuses {
+		line := fmt.Sprintf("%-6s %-9s starts: %d", status.Kind, status.State, status.Starts)
+
+		switch status.State {
+		case battleships.RoutineRunning, battleships.RoutineStopping:
+			line += fmt.Sprintf("   up: %s", time.Since(status.Started).Truncate(time.Second))
+		case battleships.RoutineStopped, battleships.RoutineExited, battleships.RoutineCrashed:
+			if !status.Started.IsZero() {
+				line += fmt.Sprintf("   ran: %s", status.Stopped.Sub(status.Started).Truncate(time.Second))
+			}
+		}
+
+		if status.Err != nil {
+			line += "   " + c.theme.TextSecondary().Render(status.Err.Error())
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder()).
+		Padding(0, 1).
+		Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,134 @@
+package wrapper
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	battleships "github.com/kovansky/wp-battleships"
+	"github.com/kovansky/wp-battleships/ships"
+	"github.com/kovansky/wp-battleships/tui"
+	"github.com/kovansky/wp-battleships/tui/board"
+	"github.com/kovansky/wp-battleships/tui/lobby"
+	"github.com/kovansky/wp-battleships/tui/ranking"
+	"github.com/kovansky/wp-battleships/tui/setup"
+	"github.com/kovansky/wp-battleships/tui/wait"
+)
+
+// changeStage moves to the requested stage, if the transition is allowed.
+func (c Application) changeStage(msg tui.ApplicationStageChangeMsg) (Application, tea.Cmd) {
+	// The message was sent from a stage we have left in the meantime, e.g. by a routine which was being stopped
+	if msg.From != "" && msg.From != c.stages.Current() {
+		return c, nil
+	}
+
+	stages, err := c.stages.Transition(msg.Stage)
+	if err != nil {
+		return c.showError(tui.ErrorMsg{Message: "Couldn't change the screen", Err: err})
+	}
+	c.abandonLeft(c.stages.Current(), msg.Stage)
+	c.stages = stages
+
+	return c.enterStage(msg.Model)
+}
+
+// back returns to the previous stage. Returns false if the current stage does not allow going back.
+func (c Application) back() (Application, tea.Cmd, bool) {
+	stages, err := c.stages.Back()
+	if err != nil {
+		return c, nil, false
+	}
+	c.abandonLeft(c.stages.Current(), stages.Current())
+	c.stages = stages
+
+	c, cmd := c.enterStage(nil)
+
+	return c, cmd, true
+}
+
+// abandonLeft gives up the game of the stage we have left, unless it goes on in the next stage.
+func (c Application) abandonLeft(from, to tui.Stage) {
+	switch from {
+	case tui.StageWait:
+		if to != tui.StageGame {
+			c.session.Abandon(c.session.Game())
+		}
+	case tui.StageGame:
+		c.session.Abandon(c.game.Game)
+	}
+}
+
+// enterStage sets up the model of the current stage. Without the model, the one the stage had before is kept.
+func (c Application) enterStage(model tea.Model) (Application, tea.Cmd) {
+	var (
+		tmp  tea.Model
+		cmd  tea.Cmd
+		cmds []tea.Cmd
+	)
+
+	// The stage has changed, so the error does not apply anymore
+	c.err = nil
+
+	size := tea.WindowSizeMsg{
+		Width:  c.width,
+		Height: c.height,
+	}
+
+	switch c.stages.Current() {
+	case tui.StageLogin:
+		break
+	case tui.StageRanking:
+		players, _ := c.session.Client.Stats()
+
+		if nick := c.session.Player().Nick; len(nick) > 0 {
+			player, err := c.session.Client.PlayerStats(nick)
+			if err == nil {
+				players = append(players, ships.NewPlayerFromStats(player))
+			}
+		}
+
+		c.ranking = ranking.Create(c.ctx, c.session, c.theme, players)
+		cmds = append(cmds, c.ranking.Init())
+
+		tmp, cmd = c.ranking.Update(size)
+		c.ranking = tmp.(ranking.Ranking)
+		cmds = append(cmds, cmd)
+	case tui.StageLobby:
+		if model != nil {
+			c.lobby = model.(lobby.Lobby)
+		}
+
+		cmds = append(cmds, c.lobby.Init())
+
+		tmp, cmd = c.lobby.Update(size)
+		c.lobby = tmp.(lobby.Lobby)
+		cmds = append(cmds, cmd)
+	case tui.StageSetup:
+		c.setup = model.(setup.Setup)
+
+		cmds = append(cmds, c.setup.Init())
+
+		tmp, cmd = c.setup.Update(size)
+		c.setup = tmp.(setup.Setup)
+		cmds = append(cmds, cmd)
+	case tui.StageWait:
+		c.wait = model.(wait.Wait)
+
+		cmds = append(cmds, c.wait.Init())
+
+		tmp, cmd = c.wait.Update(size)
+		c.wait = tmp.(wait.Wait)
+		cmds = append(cmds, cmd)
+	case tui.StageGame:
+		c.game = model.(board.Full)
+
+		cmds = append(cmds, c.game.Init())
+
+		tmp, cmd = c.game.Update(size)
+		c.game = tmp.(board.Full)
+		cmds = append(cmds, cmd)
+	}
+
+	// The theme may have been switched since the model was created
+	c, cmd = c.updateStage(battleships.ThemesChangeMsg{Name: c.session.Settings.Theme, Themes: c.session.Themes()})
+	cmds = append(cmds, cmd)
+
+	return c, tea.Batch(cmds...)
+}
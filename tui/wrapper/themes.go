@@ -0,0 +1,65 @@
+package wrapper
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	battleships "github.com/kovansky/wp-battleships"
+	"github.com/kovansky/wp-battleships/tui"
+	"github.com/kovansky/wp-battleships/tui/common"
+	"github.com/kovansky/wp-battleships/tui/themes"
+)
+
+// themeChoices lists the presets, and the player's own theme file if one is used.
+func themeChoices(current string) []string {
+	choices := append([]string(nil), themes.Presets...)
+	if themes.IsPreset(current) {
+		return choices
+	}
+
+	return append(choices, current)
+}
+
+// switchTheme moves on to the next theme.
+func (c Application) switchTheme() (Application, tea.Cmd) {
+	next := c.themeChoices[0]
+	for i, choice := range c.themeChoices {
+		if choice == c.session.Settings.Theme {
+			next = c.themeChoices[(i+1)%len(c.themeChoices)]
+		}
+	}
+
+	// Even if it's broken, the theme is skipped the next time
+	c.session.Settings.Theme = next
+
+	gameThemes, err := themes.Resolve(next)
+	if err != nil {
+		return c.showError(tui.ErrorMsg{Message: "Couldn't load the theme " + next, Err: err})
+	}
+
+	c, cmd := c.applyThemes(gameThemes)
+
+	return c, tea.Batch(cmd, tui.Notify(tui.SeverityInfo, "Theme: "+next))
+}
+
+// reloadThemes applies the theme file changed on disk, unless the player has switched to another theme since.
+func (c Application) reloadThemes(msg battleships.ThemesChangeMsg) (Application, tea.Cmd) {
+	if msg.Name != c.session.Settings.Theme {
+		return c, nil
+	}
+
+	c, cmd := c.applyThemes(msg.Themes)
+
+	return c, tea.Batch(cmd, tui.Notify(tui.SeverityInfo, "Reloaded the theme from "+msg.Name))
+}
+
+// applyThemes redraws the application with the themes.
+func (c Application) applyThemes(gameThemes battleships.GameThemes) (Application, tea.Cmd) {
+	c.session.SetThemes(gameThemes)
+	c.theme = gameThemes.Global
+
+	msg := battleships.ThemesChangeMsg{Name: c.session.Settings.Theme, Themes: gameThemes}
+
+	tmp, _ := c.toasts.Update(msg)
+	c.toasts = tmp.(common.Toasts)
+
+	return c.updateStage(msg)
+}